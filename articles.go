@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/readability"
+	"github.com/google/uuid"
+)
+
+const (
+	// agg extracts at most this many articles after each pass, so a feed that was just
+	// switched on catches up over a few passes instead of holding up the next fetch
+	articleBatchSize = 10
+	// pages larger than this are cut off before extraction
+	maxArticleBytes = 5 << 20
+)
+
+// fetchArticle downloads the page of a post and extracts its main content
+func fetchArticle(ctx context.Context, s *state, pageURL string) (readability.Article, error) {
+	client, err := feedClient(s, pageURL)
+	if err != nil {
+		return readability.Article{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.fetch.withDefaults().timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return readability.Article{}, fmt.Errorf("error in creating a request to the url: %w", err)
+	}
+	req.Header.Add("User-Agent", "gator")
+	req.Header.Add("Accept", "text/html,application/xhtml+xml")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return readability.Article{}, fmt.Errorf("error in getting a response: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return readability.Article{}, fmt.Errorf("the page returned status %d", res.StatusCode)
+	}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			return readability.Article{}, fmt.Errorf("the page is %s, not html", mediaType)
+		}
+	}
+
+	// Links in the article are resolved against the page it ended up on after redirects
+	article, err := readability.Extract(io.LimitReader(res.Body, maxArticleBytes), res.Request.URL)
+	if err != nil {
+		return readability.Article{}, fmt.Errorf("error in extracting the article: %w", err)
+	}
+	return article, nil
+}
+
+// saveArticle fetches the article of a post and stores it, a failure is stored on the post instead
+func saveArticle(ctx context.Context, s *state, postID uuid.UUID, pageURL string) (readability.Article, error) {
+	article, fetchErr := fetchArticle(ctx, s, pageURL)
+	if fetchErr != nil {
+		err := s.db.RecordArticleError(ctx, database.RecordArticleErrorParams{
+			ID:           postID,
+			ArticleError: sql.NullString{String: fetchErr.Error(), Valid: true},
+		})
+		if err != nil {
+			return article, fmt.Errorf("error in recording the failed extraction of %s: %w", pageURL, err)
+		}
+		return article, fetchErr
+	}
+
+	err := s.db.SaveArticle(ctx, database.SaveArticleParams{
+		ID:          postID,
+		ArticleHtml: sql.NullString{String: article.HTML, Valid: true},
+		ArticleText: sql.NullString{String: article.Text, Valid: true},
+	})
+	if err != nil {
+		return article, fmt.Errorf("error in saving the article of %s: %w", pageURL, err)
+	}
+	return article, nil
+}
+
+// extractArticles is called by agg after every pass and extracts the articles of new posts in
+// feeds with extraction on. It returns how many posts it tried and how many of them worked,
+// a page that fails is not tried again.
+func extractArticles(ctx context.Context, s *state) (tried, extracted int, err error) {
+	if ctx.Err() != nil {
+		return 0, 0, nil
+	}
+
+	posts, err := s.db.GetPostsToExtract(ctx, articleBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error in finding the posts to extract: %w", err)
+	}
+
+	for _, post := range posts {
+		if ctx.Err() != nil {
+			break
+		}
+		tried++
+
+		_, err := saveArticle(ctx, s, post.ID, post.Url)
+		if err != nil {
+			fmt.Printf("Couldn't extract the article of %s: %v\n", post.Url, err)
+			continue
+		}
+		extracted++
+	}
+	return tried, extracted, nil
+}
+
+// handlerFeedExtract turns full article extraction on or off for a feed
+func handlerFeedExtract(s *state, cmd command) error {
+	fs := flag.NewFlagSet("feedextract", flag.ContinueOnError)
+	off := fs.Bool("off", false, "stop extracting the articles of the feed, stored articles are kept")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the feedextract flags: %w", err)
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("enter the feedextract command along with the feed url")
+	}
+
+	ctx := context.Background()
+	updated, err := s.db.SetFeedExtractArticles(ctx, database.SetFeedExtractArticlesParams{
+		FeedUrl:         args[0],
+		ExtractArticles: !*off,
+	})
+	if err != nil {
+		return fmt.Errorf("error in saving the extraction setting of the feed: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("feed with URL %s not found", args[0])
+	}
+
+	if *off {
+		fmt.Println("Article extraction turned off for", args[0])
+		return nil
+	}
+	fmt.Println("Article extraction turned on for", args[0])
+	fmt.Println("agg fetches the full article of new posts of the feed after every pass")
+	return nil
+}
+
+// handlerArticle prints the extracted article of a post, fetching it first if it wasn't yet
+func handlerArticle(s *state, cmd command) error {
+	fs := flag.NewFlagSet("article", flag.ContinueOnError)
+	asHTML := fs.Bool("html", false, "print the cleaned html instead of the plain text")
+	refetch := fs.Bool("refetch", false, "fetch and extract the article again")
+	addHTTPCacheFlags(fs, s)
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the article flags: %w", err)
+	}
+	if err := checkReplay(s, "article"); err != nil {
+		return err
+	}
+
+	postID, err := parsePostID(command{name: cmd.name, args: args})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	post, err := s.db.GetPost(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("post %s doesn't exist", postID)
+	}
+	if err != nil {
+		return fmt.Errorf("error in fetching the post: %w", err)
+	}
+
+	articleHTML, articleText := post.ArticleHtml.String, post.ArticleText.String
+	if *refetch || !post.ArticleText.Valid {
+		article, err := saveArticle(ctx, s, post.ID, post.Url)
+		if err != nil {
+			return err
+		}
+		articleHTML, articleText = article.HTML, article.Text
+	}
+
+	fmt.Println("Title:", post.Title)
+	fmt.Println("Link:", post.Url)
+	fmt.Println("Feed:", post.FeedName)
+	fmt.Println("----------------------------------------")
+	if *asHTML {
+		fmt.Println(articleHTML)
+	} else {
+		fmt.Println(articleText)
+	}
+	return nil
+}
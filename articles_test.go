@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func Test_extractArticles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/story":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, `<html><body>
+				<nav><a href="/">Home</a> <a href="/sport">Sport</a></nav>
+				<article><div class="content">
+					<p>The harbour bridge reopened on Friday after eighteen months of repairs, officials said, ahead of schedule.</p>
+					<p>Commuters, cyclists and ferry operators had waited for the reopening, which restores the shortest route into the city.</p>
+				</div></article>
+				<div class="comments"><p>Great news, finally, about time, well done everyone involved here.</p></div>
+			</body></html>`)
+		case "/podcast.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+			io.WriteString(w, "ID3")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "City", server.URL+"/rss")
+	ctx := context.Background()
+
+	items := []RSSItem{
+		{Title: "Bridge reopens", Link: server.URL + "/story", Description: "Read more..."},
+		{Title: "Weekly podcast", Link: server.URL + "/podcast.mp3"},
+	}
+	if _, err := savePosts(ctx, s, feed.ID, items); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+
+	// Nothing is fetched until the feed opts in
+	if tried, _, err := extractArticles(ctx, s); err != nil || tried != 0 {
+		t.Fatalf("extractArticles() before feedextract = %d, %v, want nothing tried", tried, err)
+	}
+	if err := handlerFeedExtract(s, command{name: "feedextract", args: []string{feed.FeedUrl}}); err != nil {
+		t.Fatalf("handlerFeedExtract() error = %v", err)
+	}
+
+	tried, extracted, err := extractArticles(ctx, s)
+	if err != nil || tried != 2 || extracted != 1 {
+		t.Fatalf("extractArticles() = %d, %d, %v, want 2 tried and 1 extracted", tried, extracted, err)
+	}
+	if tried, _, _ := extractArticles(ctx, s); tried != 0 {
+		t.Errorf("extractArticles() tried %d posts again, want failed and extracted posts left alone", tried)
+	}
+
+	results, err := s.db.SearchPosts(ctx, database.SearchPostsParams{Query: "harbour & bridge", ResultLimit: 10})
+	if err != nil {
+		t.Fatalf("SearchPosts() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("SearchPosts() = %+v, want the post found by its article text", results)
+	}
+
+	post, err := s.db.GetPost(ctx, results[0].ID)
+	if err != nil {
+		t.Fatalf("GetPost() error = %v", err)
+	}
+	if !strings.Contains(post.ArticleText.String, "eighteen months of repairs") || !strings.Contains(post.ArticleText.String, "Commuters") {
+		t.Errorf("ArticleText = %q, want both paragraphs of the story", post.ArticleText.String)
+	}
+	for _, junk := range []string{"Sport", "Great news"} {
+		if strings.Contains(post.ArticleText.String, junk) || strings.Contains(post.ArticleHtml.String, junk) {
+			t.Errorf("article kept %q:\n%s", junk, post.ArticleHtml.String)
+		}
+	}
+
+	search, err := s.db.SearchPosts(ctx, database.SearchPostsParams{Query: "podcast", ResultLimit: 10})
+	if err != nil || len(search) != 1 {
+		t.Fatalf("SearchPosts(podcast) = %+v, %v", search, err)
+	}
+	podcast, _ := s.db.GetPost(ctx, search[0].ID)
+	if podcast.ArticleText.Valid || !strings.Contains(podcast.ArticleError.String, "not html") {
+		t.Errorf("podcast post = %q, %q, want the error recorded and no article", podcast.ArticleText.String, podcast.ArticleError.String)
+	}
+}
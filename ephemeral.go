@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// runEphemeral reads one command per line from in, written the same way as after `go run .`,
+// and runs each against the in-memory store on s, so users, feeds and posts live until the
+// session ends. A failing command prints its error and the session goes on; "exit" or the
+// end of input stops it.
+func runEphemeral(s *state, c *commands, in io.Reader) error {
+	fmt.Println("Ephemeral session, nothing is saved. Type a command, or exit to quit.")
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Print("gator> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		args, err := splitCommandLine(scanner.Text())
+		if err != nil {
+			fmt.Println("error in reading the command:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+
+		err = c.run(s, command{name: args[0], args: args[1:]})
+		if err != nil {
+			fmt.Println("error running the command", err)
+		}
+		fmt.Println()
+	}
+}
+
+// splitCommandLine splits a line into arguments the way a shell would: on spaces, with single
+// or double quotes around arguments that contain spaces and a backslash escaping the next
+// character outside single quotes. As in a shell, quotes that should reach the command, like
+// the ones around a search phrase, go inside the other kind: search '"climate change" -sports'
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	escaped := false
+	var quote rune
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("nothing to escape after the backslash at the end of the line")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/config"
+	"github.com/Pradhyumna789/RSS/internal/database/memory"
+)
+
+func Test_splitCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`addfeed "Hacker News" https://news.ycombinator.com/rss`, []string{"addfeed", "Hacker News", "https://news.ycombinator.com/rss"}},
+		{`search '"climate change" -sports'`, []string{"search", `"climate change" -sports`}},
+		{`search "\"climate change\"" -- -sports`, []string{"search", `"climate change"`, "--", "-sports"}},
+		{`search \"climate\ change\"`, []string{"search", `"climate change"`}},
+		{`browse   10  `, []string{"browse", "10"}},
+		{`login ''`, []string{"login", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitCommandLine(tt.line)
+		if err != nil {
+			t.Errorf("splitCommandLine(%q) error = %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{`search "climate`, `search climate\`} {
+		if _, err := splitCommandLine(line); err == nil {
+			t.Errorf("splitCommandLine(%q) error = nil", line)
+		}
+	}
+}
+
+func Test_runEphemeral(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss><channel><title>Example</title>
+			<item><title>Climate change summit</title><link>https://example.com/summit</link></item>
+			<item><title>Change of climate in the league</title><link>https://example.com/league</link></item>
+		</channel></rss>`)
+	}))
+	defer server.Close()
+
+	c := &commands{commandSystem: make(map[string]func(*state, command) error)}
+	c.register("register", handlerRegister)
+	c.register("users", handlerUsers)
+	c.register("addfeed", middlewareLoggedIn(handlerAddFeed))
+	c.register("feeds", handlerFeeds)
+	c.register("agg", handlerAgg)
+	c.register("search", handlerSearch)
+
+	s := &state{db: memory.NewStore(), config: &config.Config{}, workerID: "test-worker", ephemeral: true}
+	session := strings.Join([]string{
+		"register alice",
+		"register alice",
+		"users",
+		`addfeed "Example feed" ` + server.URL,
+		"feeds",
+		"agg --once",
+		`search '"climate change"'`,
+		"exit",
+		"users",
+	}, "\n")
+
+	output, err := captureOutput(t, func() error {
+		return runEphemeral(s, c, strings.NewReader(session))
+	})
+	if err != nil {
+		t.Fatalf("runEphemeral() error = %v", err)
+	}
+
+	// a failing command doesn't end the session, exit does
+	for _, want := range []string{"already exists", "* alice (current)", "Feed Name: Example feed", "Title: Climate change summit"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("session output is missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "(current)") != 1 {
+		t.Errorf("users ran after exit:\n%s", output)
+	}
+	// each command's output follows its prompt, the search is the seventh command
+	search := strings.Split(output, "gator> ")[7]
+	if !strings.Contains(search, "Climate change summit") || strings.Contains(search, "league") {
+		t.Errorf("phrase search printed:\n%s\nwant only the post with the phrase", search)
+	}
+}
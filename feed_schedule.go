@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/schedule"
+)
+
+func parseQuietHours(value string) (*schedule.QuietHours, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return schedule.ParseQuietHours(value)
+}
+
+// feedPolicy reads the cron schedule and quiet hours set with feedschedule.
+// They are validated before they are saved, so a value that no longer parses is ignored.
+func feedPolicy(cronSchedule, quietHours sql.NullString) schedule.Policy {
+	var policy schedule.Policy
+
+	if cronSchedule.Valid {
+		cron, err := schedule.ParseCron(cronSchedule.String)
+		if err != nil {
+			fmt.Println("Ignoring the cron schedule of the feed:", err)
+		}
+		policy.Cron = cron
+	}
+	if quietHours.Valid {
+		quiet, err := schedule.ParseQuietHours(quietHours.String)
+		if err != nil {
+			fmt.Println("Ignoring the quiet hours of the feed:", err)
+		}
+		policy.Quiet = quiet
+	}
+
+	return policy
+}
+
+// handlerFeedSchedule sets or clears the cron schedule and quiet hours of a single feed
+func handlerFeedSchedule(s *state, cmd command) error {
+	fs := flag.NewFlagSet("feedschedule", flag.ContinueOnError)
+	cronExpr := fs.String("cron", "", "only fetch the feed at times matching this cron schedule, like \"0 7-22 * * MON-FRI\"")
+	quietHours := fs.String("quiet-hours", "", "never fetch the feed inside this daily window, like 22:00-07:00 (local time)")
+	clear := fs.Bool("clear", false, "remove the cron schedule and quiet hours of the feed")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the feedschedule flags: %w", err)
+	}
+
+	if len(args) < 1 || (!*clear && *cronExpr == "" && *quietHours == "") {
+		return fmt.Errorf("enter the feedschedule command along with the feed url and --cron, --quiet-hours or --clear")
+	}
+
+	ctx := context.Background()
+	if *clear {
+		cleared, err := s.db.ClearFeedSchedule(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("error in clearing the schedule of the feed: %w", err)
+		}
+		if cleared == 0 {
+			return fmt.Errorf("feed with URL %s not found", args[0])
+		}
+		if _, err := rescheduleFeed(ctx, s, args[0]); err != nil {
+			return err
+		}
+		fmt.Println("Feed schedule cleared")
+		return nil
+	}
+
+	// A flag that isn't given stays NULL, which keeps the feed's current setting
+	params := database.SetFeedScheduleParams{FeedUrl: args[0]}
+	if *cronExpr != "" {
+		cron, err := schedule.ParseCron(*cronExpr)
+		if err != nil {
+			return err
+		}
+		params.CronSchedule = sql.NullString{String: cron.String(), Valid: true}
+	}
+	if *quietHours != "" {
+		quiet, err := schedule.ParseQuietHours(*quietHours)
+		if err != nil {
+			return err
+		}
+		params.QuietHours = sql.NullString{String: quiet.String(), Valid: true}
+	}
+
+	updated, err := s.db.SetFeedSchedule(ctx, params)
+	if err != nil {
+		return fmt.Errorf("error in saving the schedule of the feed: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("feed with URL %s not found", params.FeedUrl)
+	}
+
+	nextFetchAt, err := rescheduleFeed(ctx, s, params.FeedUrl)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Feed schedule updated")
+	if params.CronSchedule.Valid {
+		fmt.Println("Cron:", params.CronSchedule.String)
+	}
+	if params.QuietHours.Valid {
+		fmt.Println("Quiet hours:", params.QuietHours.String)
+	}
+	fmt.Println("Next fetch:", nextFetchAt.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// rescheduleFeed moves the next fetch of the feed to the first moment its saved schedule allows,
+// otherwise the feed would be fetched once more at the time the old schedule picked
+func rescheduleFeed(ctx context.Context, s *state, feedUrl string) (time.Time, error) {
+	feed, err := s.db.GetFeedSchedule(ctx, feedUrl)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error in reading the schedule of the feed: %w", err)
+	}
+
+	nextFetchAt := feedPolicy(feed.CronSchedule, feed.QuietHours).Next(time.Now())
+	err = s.db.SetFeedNextFetch(ctx, database.SetFeedNextFetchParams{
+		ID:          feed.ID,
+		NextFetchAt: sql.NullTime{Time: nextFetchAt, Valid: true},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error in rescheduling the feed: %w", err)
+	}
+
+	return nextFetchAt, nil
+}
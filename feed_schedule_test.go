@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_handlerFeedSchedule(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+
+	run := func(args ...string) {
+		t.Helper()
+		if err := handlerFeedSchedule(s, command{name: "feedschedule", args: append([]string{feed.FeedUrl}, args...)}); err != nil {
+			t.Fatalf("handlerFeedSchedule(%v) error = %v", args, err)
+		}
+	}
+	current := func() (string, string) {
+		t.Helper()
+		feeds, err := s.db.GetFeeds(context.Background())
+		if err != nil || len(feeds) != 1 {
+			t.Fatalf("GetFeeds() = %v, %v", feeds, err)
+		}
+		return feeds[0].CronSchedule.String, feeds[0].QuietHours.String
+	}
+
+	run("--quiet-hours", "22:00-07:00")
+	run("--cron", "0 8 * * *")
+	if cron, quiet := current(); cron != "0 8 * * *" || quiet != "22:00-07:00" {
+		t.Errorf("after setting --cron, schedule = %q, %q, want the quiet hours kept", cron, quiet)
+	}
+
+	run("--quiet-hours", "23:00-06:00")
+	if cron, quiet := current(); cron != "0 8 * * *" || quiet != "23:00-06:00" {
+		t.Errorf("after setting --quiet-hours, schedule = %q, %q, want the cron kept", cron, quiet)
+	}
+
+	run("--clear")
+	if cron, quiet := current(); cron != "" || quiet != "" {
+		t.Errorf("after --clear, schedule = %q, %q, want both removed", cron, quiet)
+	}
+}
+
+func Test_handlerFeedSchedule_reschedules(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	ctx := context.Background()
+
+	nextFetch := func(args ...string) time.Time {
+		t.Helper()
+		if _, err := captureOutput(t, func() error {
+			return handlerFeedSchedule(s, command{name: "feedschedule", args: append([]string{feed.FeedUrl}, args...)})
+		}); err != nil {
+			t.Fatalf("handlerFeedSchedule(%v) error = %v", args, err)
+		}
+		feeds, err := s.db.GetFeeds(ctx)
+		if err != nil {
+			t.Fatalf("GetFeeds() error = %v", err)
+		}
+		return feeds[0].NextFetchAt.Time
+	}
+
+	// a new feed is due right away, the cron schedule moves it to the next 08:00
+	now := time.Now()
+	want := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, time.Local)
+	if !want.After(now) {
+		want = want.AddDate(0, 0, 1)
+	}
+	if got := nextFetch("--cron", "0 8 * * *"); !got.Equal(want) {
+		t.Errorf("next fetch after --cron = %v, want %v", got, want)
+	}
+	if claimed := claimAll(t, s, s.workerID); claimed != 0 {
+		t.Errorf("agg claimed %d feeds before the cron schedule allows it, want 0", claimed)
+	}
+
+	if got := nextFetch("--clear"); got.After(time.Now()) {
+		t.Errorf("next fetch after --clear = %v, want the feed due now", got)
+	}
+	if claimed := claimAll(t, s, s.workerID); claimed != 1 {
+		t.Errorf("agg claimed %d feeds after --clear, want the feed", claimed)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func writeFetchLog(ctx context.Context, s *state, logEntry database.CreateFetchLogParams, fetchErr error) error {
+	if fetchErr != nil {
+		logEntry.Error = sql.NullString{String: fetchErr.Error(), Valid: true}
+	}
+
+	err := s.db.CreateFetchLog(ctx, logEntry)
+	if err != nil {
+		return fmt.Errorf("error in writing the fetch log: %w", err)
+	}
+
+	return nil
+}
+
+// handlerFeedStats summarizes the fetch_log of one feed, or of every feed when no url is given
+func handlerFeedStats(s *state, cmd command) error {
+	fs := flag.NewFlagSet("feedstats", flag.ContinueOnError)
+	days := fs.Int("days", 7, "number of days of fetch history to summarize")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the feedstats flags: %w", err)
+	}
+
+	if *days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	feedUrl := ""
+	if len(args) > 0 {
+		feedUrl = args[0]
+	}
+
+	ctx := context.Background()
+	stats, err := s.db.GetFeedStats(ctx, database.GetFeedStatsParams{
+		Since:   time.Now().AddDate(0, 0, -*days),
+		FeedUrl: feedUrl,
+	})
+	if err != nil {
+		return fmt.Errorf("error in fetching the feed stats: %w", err)
+	}
+
+	if len(stats) == 0 {
+		if feedUrl != "" {
+			return fmt.Errorf("feed with URL %s not found", feedUrl)
+		}
+		fmt.Println("No feeds")
+		return nil
+	}
+
+	fmt.Printf("Fetch statistics for the last %d days\n", *days)
+	for _, stat := range stats {
+		successRate := 0.0
+		if stat.Attempts > 0 {
+			successRate = float64(stat.Successes) / float64(stat.Attempts) * 100
+		}
+
+		fmt.Println("Feed Name:", stat.FeedName)
+		fmt.Println("Feed URL:", stat.FeedUrl)
+		fmt.Printf("Fetches: %d (%d ok, %.1f%% success)\n", stat.Attempts, stat.Successes, successRate)
+		fmt.Printf("Median latency: %s\n", time.Duration(stat.MedianDurationMs*float64(time.Millisecond)).Round(time.Millisecond))
+		fmt.Printf("New items per day: %.2f\n", float64(stat.NewItems)/float64(*days))
+		fmt.Println("--------------------------------")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+func Test_handlerFeedStats(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+
+	entries := []struct {
+		age        time.Duration
+		durationMs int32
+		newItems   int32
+		failed     bool
+	}{
+		{2 * 24 * time.Hour, 100, 4, false},
+		{2 * 24 * time.Hour, 300, 0, true},
+		{10 * 24 * time.Hour, 1000, 10, false},
+	}
+	for _, entry := range entries {
+		var fetchErr error
+		if entry.failed {
+			fetchErr = &fetchStatusError{URL: feed.FeedUrl, StatusCode: http.StatusInternalServerError}
+		}
+		err := writeFetchLog(context.Background(), s, database.CreateFetchLogParams{
+			ID:         uuid.New(),
+			FeedID:     feed.ID,
+			StartedAt:  time.Now().Add(-entry.age),
+			DurationMs: entry.durationMs,
+			HttpStatus: sql.NullInt32{Int32: 200, Valid: !entry.failed},
+			NewItems:   entry.newItems,
+		}, fetchErr)
+		if err != nil {
+			t.Fatalf("writeFetchLog() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default week", nil, []string{"last 7 days", "Fetches: 2 (1 ok, 50.0% success)", "Median latency: 200ms", "New items per day: 0.57"}},
+		{"month", []string{"--days", "30", feed.FeedUrl}, []string{"last 30 days", "Fetches: 3 (2 ok, 66.7% success)", "Median latency: 300ms", "New items per day: 0.47"}},
+		{"one day", []string{"--days", "1"}, []string{"last 1 days", "Fetches: 0 (0 ok, 0.0% success)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := captureOutput(t, func() error {
+				return handlerFeedStats(s, command{name: "feedstats", args: tt.args})
+			})
+			if err != nil {
+				t.Fatalf("handlerFeedStats() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("feedstats %v = %q, want it to contain %q", tt.args, output, want)
+				}
+			}
+		})
+	}
+
+	if err := handlerFeedStats(s, command{name: "feedstats", args: []string{"--days", "0"}}); err == nil {
+		t.Errorf("feedstats --days 0, want an error")
+	}
+	if err := handlerFeedStats(s, command{name: "feedstats", args: []string{"https://missing.example/rss"}}); err == nil {
+		t.Errorf("feedstats of an unknown feed, want an error")
+	}
+}
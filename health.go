@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+// Feed health classes, from worst to best
+const (
+	healthDead     = "dead"
+	healthErroring = "erroring"
+	healthStale    = "stale"
+	healthSlow     = "slow"
+	healthPaused   = "paused"
+	healthPending  = "pending"
+	healthHealthy  = "healthy"
+)
+
+type healthThresholds struct {
+	staleAfter time.Duration
+	slowerThan time.Duration
+}
+
+// classifyFeed picks the worst class that applies to a feed.
+// medianLatency comes from the fetch log, zero means the feed has no recent fetches.
+func classifyFeed(feed database.Feed, medianLatency time.Duration, thresholds healthThresholds, now time.Time) string {
+	switch {
+	case feed.Status == feedStatusDead:
+		return healthDead
+	case feed.LastErrorAt.Valid && (!feed.LastSuccessAt.Valid || feed.LastErrorAt.Time.After(feed.LastSuccessAt.Time)):
+		return healthErroring
+	case feed.Status == feedStatusPaused:
+		return healthPaused
+	case !feed.LastSuccessAt.Valid:
+		return healthPending
+	}
+
+	lastNew := feed.Createdat
+	if feed.LastNewItemAt.Valid {
+		lastNew = feed.LastNewItemAt.Time
+	}
+	if now.Sub(lastNew) > thresholds.staleAfter {
+		return healthStale
+	}
+
+	if medianLatency > thresholds.slowerThan {
+		return healthSlow
+	}
+
+	return healthHealthy
+}
+
+// handlerHealth classifies every feed and prints the problems first.
+// With --check it returns an error, and so exits non-zero, when any feed is dead, erroring, stale or slow.
+func handlerHealth(s *state, cmd command) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	staleDays := fs.Int("stale-days", 30, "a feed without new items for this many days is stale")
+	slow := fs.Duration("slow", 10*time.Second, "a feed whose median fetch over the last 7 days takes longer than this is slow")
+	check := fs.Bool("check", false, "exit non-zero when any feed is not healthy, for monitoring scripts")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the health flags: %w", err)
+	}
+
+	ctx := context.Background()
+	feeds, err := s.db.GetFeeds(ctx)
+	if err != nil {
+		return fmt.Errorf("error in fetching all the feeds from the database: %w", err)
+	}
+
+	stats, err := s.db.GetFeedStats(ctx, database.GetFeedStatsParams{Since: time.Now().AddDate(0, 0, -7)})
+	if err != nil {
+		return fmt.Errorf("error in fetching the feed stats: %w", err)
+	}
+	latency := make(map[uuid.UUID]time.Duration)
+	for _, stat := range stats {
+		latency[stat.ID] = time.Duration(stat.MedianDurationMs * float64(time.Millisecond))
+	}
+
+	thresholds := healthThresholds{
+		staleAfter: time.Duration(*staleDays) * 24 * time.Hour,
+		slowerThan: *slow,
+	}
+
+	order := []string{healthDead, healthErroring, healthStale, healthSlow, healthPaused, healthPending, healthHealthy}
+	byClass := make(map[string][]database.Feed)
+	now := time.Now()
+	for _, feed := range feeds {
+		class := classifyFeed(feed, latency[feed.ID], thresholds, now)
+		byClass[class] = append(byClass[class], feed)
+	}
+
+	unhealthy := 0
+	for _, class := range order {
+		for _, feed := range byClass[class] {
+			fmt.Printf("[%s] %s\n", class, feed.FeedName)
+			fmt.Println("Feed URL:", feed.FeedUrl)
+			if feed.LastSuccessAt.Valid {
+				fmt.Println("Last successful fetch:", feed.LastSuccessAt.Time)
+			} else {
+				fmt.Println("Last successful fetch: never")
+			}
+			if feed.LastNewItemAt.Valid {
+				fmt.Println("Last new item:", feed.LastNewItemAt.Time)
+			}
+			if class == healthSlow {
+				fmt.Println("Median latency:", latency[feed.ID].Round(time.Millisecond))
+			}
+			if class == healthDead && feed.StatusReason.Valid {
+				fmt.Println("Reason:", feed.StatusReason.String)
+			}
+			if feed.LastError.Valid {
+				fmt.Printf("Last error (%s): %s\n", feed.LastErrorAt.Time, feed.LastError.String)
+			}
+			fmt.Println("--------------------------------")
+		}
+
+		switch class {
+		case healthDead, healthErroring, healthStale, healthSlow:
+			unhealthy += len(byClass[class])
+		}
+	}
+
+	fmt.Println("Summary:")
+	for _, class := range order {
+		fmt.Printf("  %s: %d\n", class, len(byClass[class]))
+	}
+
+	if *check && unhealthy > 0 {
+		return fmt.Errorf("%d feeds are not healthy", unhealthy)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func Test_classifyFeed(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(-ago), Valid: true} }
+	day := 24 * time.Hour
+	thresholds := healthThresholds{staleAfter: 30 * day, slowerThan: 10 * time.Second}
+
+	tests := []struct {
+		name    string
+		feed    database.Feed
+		latency time.Duration
+		want    string
+	}{
+		{"healthy", database.Feed{Status: feedStatusActive, LastSuccessAt: at(time.Hour), LastNewItemAt: at(day)}, time.Second, healthHealthy},
+		{"dead wins", database.Feed{Status: feedStatusDead, LastErrorAt: at(time.Hour)}, 0, healthDead},
+		{"error after last success", database.Feed{Status: feedStatusActive, LastSuccessAt: at(day), LastErrorAt: at(time.Hour)}, 0, healthErroring},
+		{"recovered after error", database.Feed{Status: feedStatusActive, LastSuccessAt: at(time.Hour), LastErrorAt: at(day), LastNewItemAt: at(day)}, 0, healthHealthy},
+		{"stale", database.Feed{Status: feedStatusActive, LastSuccessAt: at(time.Hour), LastNewItemAt: at(60 * day)}, 0, healthStale},
+		{"never had new items", database.Feed{Status: feedStatusActive, Createdat: now.Add(-60 * day), LastSuccessAt: at(time.Hour)}, 0, healthStale},
+		{"slow", database.Feed{Status: feedStatusActive, LastSuccessAt: at(time.Hour), LastNewItemAt: at(day)}, time.Minute, healthSlow},
+		{"never fetched", database.Feed{Status: feedStatusActive, Createdat: now}, 0, healthPending},
+		{"paused", database.Feed{Status: feedStatusPaused, LastSuccessAt: at(time.Hour)}, 0, healthPaused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFeed(tt.feed, tt.latency, thresholds, now); got != tt.want {
+				t.Errorf("classifyFeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,8 +9,38 @@ import (
 )
 
 type Config struct {
-	DbURL           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DbURL           string    `json:"db_url"`
+	CurrentUserName string    `json:"current_user_name"`
+	TLS             TLSConfig `json:"tls,omitempty"`
+
+	// agg worker pool and schedule, see scrapeFeeds. Durations are strings like "30s".
+	FetchConcurrency int    `json:"fetch_concurrency,omitempty"`
+	FetchTimeout     string `json:"fetch_timeout,omitempty"`
+	FetchBatchSize   int    `json:"fetch_batch_size,omitempty"`
+	FetchInterval    string `json:"fetch_interval,omitempty"`
+	FetchMinInterval string `json:"fetch_min_interval,omitempty"`
+	FetchMaxInterval string `json:"fetch_max_interval,omitempty"`
+
+	// daily window in local time during which agg doesn't fetch anything, like "22:00-07:00"
+	QuietHours string `json:"quiet_hours,omitempty"`
+
+	// default retention of stored posts, see prune. 0 keeps posts forever, feedretention overrides it per feed.
+	RetentionKeepLast   int `json:"retention_keep_last,omitempty"`
+	RetentionMaxAgeDays int `json:"retention_max_age_days,omitempty"`
+}
+
+// TLSConfig controls how feeds served over https are verified.
+// ClientCertificates is keyed by feed url, for servers that require mutual TLS.
+// InsecureSkipVerify turns off certificate verification and is meant for lab environments only.
+type TLSConfig struct {
+	RootCAFiles        []string                     `json:"root_ca_files,omitempty"`
+	ClientCertificates map[string]ClientCertificate `json:"client_certificates,omitempty"`
+	InsecureSkipVerify bool                         `json:"insecure_skip_verify,omitempty"`
+}
+
+type ClientCertificate struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
 }
 
 /*
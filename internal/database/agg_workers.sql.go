@@ -0,0 +1,42 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: agg_workers.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const countLiveAggWorkers = `-- name: CountLiveAggWorkers :one
+SELECT COUNT(*) FROM agg_workers WHERE last_seen_at > $1
+`
+
+func (q *Queries) CountLiveAggWorkers(ctx context.Context, lastSeenAt time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLiveAggWorkers, lastSeenAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteAggWorker = `-- name: DeleteAggWorker :exec
+DELETE FROM agg_workers WHERE worker_id = $1
+`
+
+func (q *Queries) DeleteAggWorker(ctx context.Context, workerID string) error {
+	_, err := q.db.ExecContext(ctx, deleteAggWorker, workerID)
+	return err
+}
+
+const upsertAggWorker = `-- name: UpsertAggWorker :exec
+INSERT INTO agg_workers(worker_id, started_at, last_seen_at)
+VALUES($1, NOW(), NOW())
+ON CONFLICT (worker_id) DO UPDATE SET last_seen_at = NOW()
+`
+
+func (q *Queries) UpsertAggWorker(ctx context.Context, workerID string) error {
+	_, err := q.db.ExecContext(ctx, upsertAggWorker, workerID)
+	return err
+}
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: feed_follow.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeedFollow = `-- name: CreateFeedFollow :many
+WITH inserted_feed_follow AS(
+    INSERT INTO feed_follow(id, createdAt, updatedAt, user_id, feed_id)
+    VALUES($1, $2, $3, $4, $5)
+    RETURNING id, createdat, updatedat, user_id, feed_id
+)
+
+SELECT
+    inserted_feed_follow.id, inserted_feed_follow.createdat, inserted_feed_follow.updatedat, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
+    feeds.feed_name AS feed_name,
+    users.user_name AS user_name
+    FROM inserted_feed_follow
+    INNER JOIN users ON inserted_feed_follow.user_id = users.id 
+    INNER JOIN feeds ON inserted_feed_follow.feed_id = feeds.id
+`
+
+type CreateFeedFollowParams struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+type CreateFeedFollowRow struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+	FeedName  string
+	UserName  string
+}
+
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) ([]CreateFeedFollowRow, error) {
+	rows, err := q.db.QueryContext(ctx, createFeedFollow,
+		arg.ID,
+		arg.Createdat,
+		arg.Updatedat,
+		arg.UserID,
+		arg.FeedID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreateFeedFollowRow
+	for rows.Next() {
+		var i CreateFeedFollowRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Createdat,
+			&i.Updatedat,
+			&i.UserID,
+			&i.FeedID,
+			&i.FeedName,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteFeedFollowRecord = `-- name: DeleteFeedFollowRecord :exec
+DELETE FROM feed_follow
+WHERE feed_follow.user_id = $1
+AND feed_follow.feed_id = (SELECT feeds.id FROM feeds WHERE feeds.feed_url = $2)
+`
+
+type DeleteFeedFollowRecordParams struct {
+	UserID  uuid.UUID
+	FeedUrl string
+}
+
+func (q *Queries) DeleteFeedFollowRecord(ctx context.Context, arg DeleteFeedFollowRecordParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedFollowRecord, arg.UserID, arg.FeedUrl)
+	return err
+}
+
+const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
+SELECT 
+    users.user_name, feeds.feed_name FROM feed_follow 
+    INNER JOIN users ON feed_follow.user_id = users.id
+    INNER JOIN feeds ON feed_follow.feed_id = feeds.id
+    WHERE feed_follow.user_id = $1
+`
+
+type GetFeedFollowsForUserRow struct {
+	UserName string
+	FeedName string
+}
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i GetFeedFollowsForUserRow
+		if err := rows.Scan(&i.UserName, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
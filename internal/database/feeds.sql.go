@@ -11,8 +11,124 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const claimFeedsToFetch = `-- name: ClaimFeedsToFetch :many
+WITH due AS (
+    SELECT id
+    FROM feeds
+    WHERE status = 'active'
+    AND (next_fetch_at IS NULL OR next_fetch_at <= NOW() OR refresh_requested_at IS NOT NULL)
+    AND (NOT $3::boolean OR refresh_requested_at IS NOT NULL)
+    AND (not_before IS NULL OR not_before <= NOW())
+    AND (lease_expires_at IS NULL OR lease_expires_at <= NOW())
+    AND NOT EXISTS (
+        SELECT 1 FROM host_backoff
+        WHERE host_backoff.host = lower(substring(feeds.feed_url from '://([^/?#:]+)'))
+        AND host_backoff.not_before > NOW()
+    )
+    ORDER BY refresh_requested_at ASC NULLS LAST, next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT $4
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE feeds SET leased_by = $1, lease_expires_at = $2
+FROM due
+WHERE feeds.id = due.id
+RETURNING feeds.id, feeds.feed_name, feeds.feed_url, feeds.last_fetched_at, feeds.fetch_interval_seconds,
+    feeds.ttl_minutes, feeds.skip_hours, feeds.skip_days, feeds.update_period, feeds.update_frequency,
+    feeds.cron_schedule, feeds.quiet_hours
+`
+
+type ClaimFeedsToFetchParams struct {
+	Worker         sql.NullString
+	LeaseExpiresAt sql.NullTime
+	OnlyRequested  bool
+	BatchSize      int32
+}
+
+type ClaimFeedsToFetchRow struct {
+	ID                   uuid.UUID
+	FeedName             string
+	FeedUrl              string
+	LastFetchedAt        sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	TtlMinutes           sql.NullInt32
+	SkipHours            []int32
+	SkipDays             []string
+	UpdatePeriod         sql.NullString
+	UpdateFrequency      sql.NullInt32
+	CronSchedule         sql.NullString
+	QuietHours           sql.NullString
+}
+
+func (q *Queries) ClaimFeedsToFetch(ctx context.Context, arg ClaimFeedsToFetchParams) ([]ClaimFeedsToFetchRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimFeedsToFetch,
+		arg.Worker,
+		arg.LeaseExpiresAt,
+		arg.OnlyRequested,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimFeedsToFetchRow
+	for rows.Next() {
+		var i ClaimFeedsToFetchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.LastFetchedAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			pq.Array(&i.SkipHours),
+			pq.Array(&i.SkipDays),
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const clearFeedRetention = `-- name: ClearFeedRetention :execrows
+UPDATE feeds SET retention_keep_last = NULL, retention_max_age_days = NULL, updatedat = NOW()
+WHERE feed_url = $1
+`
+
+func (q *Queries) ClearFeedRetention(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearFeedRetention, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const clearFeedSchedule = `-- name: ClearFeedSchedule :execrows
+UPDATE feeds SET cron_schedule = NULL, quiet_hours = NULL, updatedat = NOW()
+WHERE feed_url = $1
+`
+
+func (q *Queries) ClearFeedSchedule(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearFeedSchedule, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds(id, createdAt, updatedAt, feed_name, feed_url, user_id)
 VALUES(
@@ -23,7 +139,7 @@ VALUES(
     $5,
     $6
 )
-RETURNING id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at
+RETURNING id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles
 `
 
 type CreateFeedParams struct {
@@ -53,6 +169,29 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.FeedUrl,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ConsecutiveNotFound,
+		&i.NotBefore,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.TtlMinutes,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.UpdatePeriod,
+		&i.UpdateFrequency,
+		&i.CronSchedule,
+		&i.QuietHours,
+		&i.LeasedBy,
+		&i.LeaseExpiresAt,
+		&i.RefreshRequestedAt,
+		&i.LastSuccessAt,
+		&i.LastNewItemAt,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeDays,
+		&i.ExtractArticles,
 	)
 	return i, err
 }
@@ -79,8 +218,26 @@ func (q *Queries) GetFeedNameById(ctx context.Context, id uuid.UUID) (string, er
 	return feed_name, err
 }
 
+const getFeedSchedule = `-- name: GetFeedSchedule :one
+SELECT id, cron_schedule, quiet_hours FROM feeds
+WHERE feed_url = $1
+`
+
+type GetFeedScheduleRow struct {
+	ID           uuid.UUID
+	CronSchedule sql.NullString
+	QuietHours   sql.NullString
+}
+
+func (q *Queries) GetFeedSchedule(ctx context.Context, feedUrl string) (GetFeedScheduleRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedSchedule, feedUrl)
+	var i GetFeedScheduleRow
+	err := row.Scan(&i.ID, &i.CronSchedule, &i.QuietHours)
+	return i, err
+}
+
 const getFeeds = `-- name: GetFeeds :many
-SELECT id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at FROM feeds
+SELECT id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles FROM feeds
 `
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
@@ -100,6 +257,29 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.FeedUrl,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ConsecutiveNotFound,
+			&i.NotBefore,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			pq.Array(&i.SkipHours),
+			pq.Array(&i.SkipDays),
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+			&i.LeasedBy,
+			&i.LeaseExpiresAt,
+			&i.RefreshRequestedAt,
+			&i.LastSuccessAt,
+			&i.LastNewItemAt,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeDays,
+			&i.ExtractArticles,
 		); err != nil {
 			return nil, err
 		}
@@ -114,34 +294,50 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :many
-SELECT id, feed_name, feed_url, last_fetched_at
-    FROM feeds
-    ORDER BY last_fetched_at DESC NULLS LAST
-    LIMIT 5
+const getFeedsByStatus = `-- name: GetFeedsByStatus :many
+SELECT id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles FROM feeds WHERE status = $1 ORDER BY updatedat DESC
 `
 
-type GetNextFeedToFetchRow struct {
-	ID            uuid.UUID
-	FeedName      string
-	FeedUrl       string
-	LastFetchedAt sql.NullTime
-}
-
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) ([]GetNextFeedToFetchRow, error) {
-	rows, err := q.db.QueryContext(ctx, getNextFeedToFetch)
+func (q *Queries) GetFeedsByStatus(ctx context.Context, status string) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsByStatus, status)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetNextFeedToFetchRow
+	var items []Feed
 	for rows.Next() {
-		var i GetNextFeedToFetchRow
+		var i Feed
 		if err := rows.Scan(
 			&i.ID,
+			&i.Createdat,
+			&i.Updatedat,
 			&i.FeedName,
 			&i.FeedUrl,
+			&i.UserID,
 			&i.LastFetchedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ConsecutiveNotFound,
+			&i.NotBefore,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			pq.Array(&i.SkipHours),
+			pq.Array(&i.SkipDays),
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+			&i.LeasedBy,
+			&i.LeaseExpiresAt,
+			&i.RefreshRequestedAt,
+			&i.LastSuccessAt,
+			&i.LastNewItemAt,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeDays,
+			&i.ExtractArticles,
 		); err != nil {
 			return nil, err
 		}
@@ -156,11 +352,316 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) ([]GetNextFeedToFetchR
 	return items, nil
 }
 
-const markFeedFetched = `-- name: MarkFeedFetched :exec
-UPDATE feeds SET last_fetched_at = NOW(), updatedat = NOW()
+const markFeedFetched = `-- name: MarkFeedFetched :execrows
+UPDATE feeds SET last_fetched_at = NOW(), next_fetch_at = $2, fetch_interval_seconds = $3,
+    leased_by = NULL, lease_expires_at = NULL, refresh_requested_at = NULL, updatedat = NOW()
+WHERE id = $1 AND leased_by = $4
+`
+
+type MarkFeedFetchedParams struct {
+	ID                   uuid.UUID
+	NextFetchAt          sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	LeasedBy             sql.NullString
+}
+
+// Only the worker holding the lease may reschedule the feed, a worker whose lease ran out
+// and was taken over updates nothing
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markFeedFetched,
+		arg.ID,
+		arg.NextFetchAt,
+		arg.FetchIntervalSeconds,
+		arg.LeasedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordFeedError = `-- name: RecordFeedError :exec
+UPDATE feeds SET last_error = $2, last_error_at = NOW()
+WHERE id = $1
+`
+
+type RecordFeedErrorParams struct {
+	ID        uuid.UUID
+	LastError sql.NullString
+}
+
+func (q *Queries) RecordFeedError(ctx context.Context, arg RecordFeedErrorParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedError, arg.ID, arg.LastError)
+	return err
+}
+
+const recordFeedNotFound = `-- name: RecordFeedNotFound :one
+UPDATE feeds SET consecutive_not_found = consecutive_not_found + 1, updatedat = NOW()
+WHERE id = $1
+RETURNING consecutive_not_found
+`
+
+func (q *Queries) RecordFeedNotFound(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedNotFound, id)
+	var consecutive_not_found int32
+	err := row.Scan(&consecutive_not_found)
+	return consecutive_not_found, err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :exec
+UPDATE feeds SET last_success_at = NOW(),
+    last_new_item_at = CASE WHEN $1::boolean THEN NOW() ELSE last_new_item_at END
+WHERE id = $2
+`
+
+type RecordFeedSuccessParams struct {
+	HadNewItems bool
+	ID          uuid.UUID
+}
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess, arg.HadNewItems, arg.ID)
+	return err
+}
+
+const releaseFeedLeases = `-- name: ReleaseFeedLeases :exec
+UPDATE feeds SET leased_by = NULL, lease_expires_at = NULL
+WHERE leased_by = $1
+`
+
+func (q *Queries) ReleaseFeedLeases(ctx context.Context, leasedBy sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedLeases, leasedBy)
+	return err
+}
+
+const requestAllFeedsRefresh = `-- name: RequestAllFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE status = 'active'
+`
+
+func (q *Queries) RequestAllFeedsRefresh(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestAllFeedsRefresh)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requestFeedRefresh = `-- name: RequestFeedRefresh :execrows
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE feed_url = $1
+AND status = 'active'
+`
+
+// Paused and dead feeds are never claimed, so they aren't queued either
+func (q *Queries) RequestFeedRefresh(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestFeedRefresh, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requestFollowedFeedsRefresh = `-- name: RequestFollowedFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE status = 'active'
+AND id IN (SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = $1)
+`
+
+func (q *Queries) RequestFollowedFeedsRefresh(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestFollowedFeedsRefresh, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resetFeedNotFound = `-- name: ResetFeedNotFound :exec
+UPDATE feeds SET consecutive_not_found = 0
+WHERE id = $1 AND consecutive_not_found > 0
+`
+
+func (q *Queries) ResetFeedNotFound(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resetFeedNotFound, id)
+	return err
+}
+
+const reviveFeed = `-- name: ReviveFeed :one
+UPDATE feeds SET status = 'active', status_reason = NULL, consecutive_not_found = 0, updatedat = NOW()
+WHERE feed_url = $1
+RETURNING id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles
 `
 
-func (q *Queries) MarkFeedFetched(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, markFeedFetched)
+func (q *Queries) ReviveFeed(ctx context.Context, feedUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, reviveFeed, feedUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Createdat,
+		&i.Updatedat,
+		&i.FeedName,
+		&i.FeedUrl,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ConsecutiveNotFound,
+		&i.NotBefore,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.TtlMinutes,
+		pq.Array(&i.SkipHours),
+		pq.Array(&i.SkipDays),
+		&i.UpdatePeriod,
+		&i.UpdateFrequency,
+		&i.CronSchedule,
+		&i.QuietHours,
+		&i.LeasedBy,
+		&i.LeaseExpiresAt,
+		&i.RefreshRequestedAt,
+		&i.LastSuccessAt,
+		&i.LastNewItemAt,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeDays,
+		&i.ExtractArticles,
+	)
+	return i, err
+}
+
+const setFeedExtractArticles = `-- name: SetFeedExtractArticles :execrows
+UPDATE feeds SET extract_articles = $2, updatedat = NOW()
+WHERE feed_url = $1
+`
+
+type SetFeedExtractArticlesParams struct {
+	FeedUrl         string
+	ExtractArticles bool
+}
+
+func (q *Queries) SetFeedExtractArticles(ctx context.Context, arg SetFeedExtractArticlesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedExtractArticles, arg.FeedUrl, arg.ExtractArticles)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedNextFetch = `-- name: SetFeedNextFetch :exec
+UPDATE feeds SET next_fetch_at = $2, updatedat = NOW()
+WHERE id = $1
+`
+
+type SetFeedNextFetchParams struct {
+	ID          uuid.UUID
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNextFetch, arg.ID, arg.NextFetchAt)
+	return err
+}
+
+const setFeedNotBefore = `-- name: SetFeedNotBefore :exec
+UPDATE feeds SET not_before = $2, updatedat = NOW()
+WHERE id = $1
+`
+
+type SetFeedNotBeforeParams struct {
+	ID        uuid.UUID
+	NotBefore sql.NullTime
+}
+
+func (q *Queries) SetFeedNotBefore(ctx context.Context, arg SetFeedNotBeforeParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNotBefore, arg.ID, arg.NotBefore)
+	return err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :execrows
+UPDATE feeds SET
+    retention_keep_last = COALESCE($1::int, retention_keep_last),
+    retention_max_age_days = COALESCE($2::int, retention_max_age_days),
+    updatedat = NOW()
+WHERE feed_url = $3
+`
+
+type SetFeedRetentionParams struct {
+	RetentionKeepLast   sql.NullInt32
+	RetentionMaxAgeDays sql.NullInt32
+	FeedUrl             string
+}
+
+// Only the limits that are given change, a NULL keeps what the feed has
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedRetention, arg.RetentionKeepLast, arg.RetentionMaxAgeDays, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedSchedule = `-- name: SetFeedSchedule :execrows
+UPDATE feeds SET
+    cron_schedule = COALESCE($1::text, cron_schedule),
+    quiet_hours = COALESCE($2::text, quiet_hours),
+    updatedat = NOW()
+WHERE feed_url = $3
+`
+
+type SetFeedScheduleParams struct {
+	CronSchedule sql.NullString
+	QuietHours   sql.NullString
+	FeedUrl      string
+}
+
+// Only the settings that are given change, a NULL keeps what the feed has
+func (q *Queries) SetFeedSchedule(ctx context.Context, arg SetFeedScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedSchedule, arg.CronSchedule, arg.QuietHours, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedStatus = `-- name: SetFeedStatus :exec
+UPDATE feeds SET status = $2, status_reason = $3, updatedat = NOW()
+WHERE id = $1
+`
+
+type SetFeedStatusParams struct {
+	ID           uuid.UUID
+	Status       string
+	StatusReason sql.NullString
+}
+
+func (q *Queries) SetFeedStatus(ctx context.Context, arg SetFeedStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedStatus, arg.ID, arg.Status, arg.StatusReason)
+	return err
+}
+
+const updateFeedHints = `-- name: UpdateFeedHints :exec
+UPDATE feeds SET ttl_minutes = $2, skip_hours = $3, skip_days = $4, update_period = $5, update_frequency = $6
+WHERE id = $1
+`
+
+type UpdateFeedHintsParams struct {
+	ID              uuid.UUID
+	TtlMinutes      sql.NullInt32
+	SkipHours       []int32
+	SkipDays        []string
+	UpdatePeriod    sql.NullString
+	UpdateFrequency sql.NullInt32
+}
+
+func (q *Queries) UpdateFeedHints(ctx context.Context, arg UpdateFeedHintsParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedHints,
+		arg.ID,
+		arg.TtlMinutes,
+		pq.Array(arg.SkipHours),
+		pq.Array(arg.SkipDays),
+		arg.UpdatePeriod,
+		arg.UpdateFrequency,
+	)
 	return err
 }
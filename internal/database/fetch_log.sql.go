@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: fetch_log.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFetchLog = `-- name: CreateFetchLog :exec
+INSERT INTO fetch_log(id, feed_id, started_at, duration_ms, http_status, bytes, items_parsed, new_items, error)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type CreateFetchLogParams struct {
+	ID          uuid.UUID
+	FeedID      uuid.UUID
+	StartedAt   time.Time
+	DurationMs  int32
+	HttpStatus  sql.NullInt32
+	Bytes       int64
+	ItemsParsed int32
+	NewItems    int32
+	Error       sql.NullString
+}
+
+func (q *Queries) CreateFetchLog(ctx context.Context, arg CreateFetchLogParams) error {
+	_, err := q.db.ExecContext(ctx, createFetchLog,
+		arg.ID,
+		arg.FeedID,
+		arg.StartedAt,
+		arg.DurationMs,
+		arg.HttpStatus,
+		arg.Bytes,
+		arg.ItemsParsed,
+		arg.NewItems,
+		arg.Error,
+	)
+	return err
+}
+
+const getFeedStats = `-- name: GetFeedStats :many
+SELECT
+    feeds.id,
+    feeds.feed_name,
+    feeds.feed_url,
+    COUNT(fetch_log.id)::int AS attempts,
+    (COUNT(fetch_log.id) FILTER (WHERE fetch_log.error IS NULL))::int AS successes,
+    COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY fetch_log.duration_ms), 0)::float8 AS median_duration_ms,
+    COALESCE(SUM(fetch_log.new_items), 0)::int AS new_items
+    FROM feeds
+    LEFT JOIN fetch_log ON fetch_log.feed_id = feeds.id AND fetch_log.started_at >= $1
+    WHERE $2::text = '' OR feeds.feed_url = $2::text
+    GROUP BY feeds.id, feeds.feed_name, feeds.feed_url
+    ORDER BY feeds.feed_name
+`
+
+type GetFeedStatsParams struct {
+	Since   time.Time
+	FeedUrl string
+}
+
+type GetFeedStatsRow struct {
+	ID               uuid.UUID
+	FeedName         string
+	FeedUrl          string
+	Attempts         int32
+	Successes        int32
+	MedianDurationMs float64
+	NewItems         int32
+}
+
+func (q *Queries) GetFeedStats(ctx context.Context, arg GetFeedStatsParams) ([]GetFeedStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedStats, arg.Since, arg.FeedUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedStatsRow
+	for rows.Next() {
+		var i GetFeedStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.Attempts,
+			&i.Successes,
+			&i.MedianDurationMs,
+			&i.NewItems,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: host_backoff.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const setHostBackoff = `-- name: SetHostBackoff :exec
+INSERT INTO host_backoff(host, not_before, updatedAt)
+VALUES($1, $2, NOW())
+ON CONFLICT (host) DO UPDATE
+    SET not_before = GREATEST(host_backoff.not_before, EXCLUDED.not_before),
+        updatedAt = NOW()
+`
+
+type SetHostBackoffParams struct {
+	Host      string
+	NotBefore time.Time
+}
+
+func (q *Queries) SetHostBackoff(ctx context.Context, arg SetHostBackoffParams) error {
+	_, err := q.db.ExecContext(ctx, setHostBackoff, arg.Host, arg.NotBefore)
+	return err
+}
@@ -0,0 +1,1490 @@
+// Package memory keeps the whole database in process memory. It implements database.Querier
+// for tests and the --ephemeral mode, nothing is written to disk.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/search"
+	"github.com/google/uuid"
+)
+
+// Store follows the Postgres schema, including its unique constraints and ON DELETE rules.
+// Rows are kept in insertion order so queries without ORDER BY return them the way Postgres usually does.
+type Store struct {
+	mu sync.Mutex
+
+	users       []database.User
+	feeds       []*database.Feed
+	follows     []database.FeedFollow
+	fetchLogs   []database.FetchLog
+	hostBackoff map[string]time.Time
+	workers     map[string]database.AggWorker
+	posts       []*database.Post
+	revisions   []database.PostRevision
+	pruned      []database.PrunedPost
+	reads       map[postKey]time.Time
+	stars       map[postKey]time.Time
+}
+
+// postKey is the primary key of post_reads and post_stars
+type postKey struct {
+	userID uuid.UUID
+	postID uuid.UUID
+}
+
+func NewStore() *Store {
+	return &Store{
+		hostBackoff: make(map[string]time.Time),
+		workers:     make(map[string]database.AggWorker),
+		reads:       make(map[postKey]time.Time),
+		stars:       make(map[postKey]time.Time),
+	}
+}
+
+var _ database.Querier = (*Store)(nil)
+
+func now() sql.NullTime {
+	return sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+// hostOf matches the host extraction ClaimFeedsToFetch does against host_backoff
+func hostOf(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func cloneFeed(feed *database.Feed) database.Feed {
+	c := *feed
+	c.SkipHours = slices.Clone(feed.SkipHours)
+	c.SkipDays = slices.Clone(feed.SkipDays)
+	return c
+}
+
+func (s *Store) feedByID(id uuid.UUID) *database.Feed {
+	for _, feed := range s.feeds {
+		if feed.ID == id {
+			return feed
+		}
+	}
+	return nil
+}
+
+func (s *Store) feedByURL(feedURL string) *database.Feed {
+	for _, feed := range s.feeds {
+		if feed.FeedUrl == feedURL {
+			return feed
+		}
+	}
+	return nil
+}
+
+func (s *Store) userByID(id uuid.UUID) *database.User {
+	for i := range s.users {
+		if s.users[i].ID == id {
+			return &s.users[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) postByID(id uuid.UUID) *database.Post {
+	for _, post := range s.posts {
+		if post.ID == id {
+			return post
+		}
+	}
+	return nil
+}
+
+// canonicalID is COALESCE(posts.canonical_post_id, posts.id), reads and stars are kept on the canonical post
+func canonicalID(post *database.Post) uuid.UUID {
+	if post.CanonicalPostID.Valid {
+		return post.CanonicalPostID.UUID
+	}
+	return post.ID
+}
+
+func (s *Store) isFollowing(userID, feedID uuid.UUID) bool {
+	for _, follow := range s.follows {
+		if follow.UserID == userID && follow.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteFeeds removes the matching feeds and everything that references them
+func (s *Store) deleteFeeds(match func(*database.Feed) bool) {
+	removed := make(map[uuid.UUID]bool)
+	s.feeds = slices.DeleteFunc(s.feeds, func(feed *database.Feed) bool {
+		if match(feed) {
+			removed[feed.ID] = true
+			return true
+		}
+		return false
+	})
+
+	s.follows = slices.DeleteFunc(s.follows, func(follow database.FeedFollow) bool {
+		return removed[follow.FeedID]
+	})
+	s.fetchLogs = slices.DeleteFunc(s.fetchLogs, func(entry database.FetchLog) bool {
+		return removed[entry.FeedID]
+	})
+	s.pruned = slices.DeleteFunc(s.pruned, func(pruned database.PrunedPost) bool {
+		return removed[pruned.FeedID]
+	})
+	s.deletePosts(func(post *database.Post) bool {
+		return removed[post.FeedID]
+	})
+}
+
+// deletePosts removes the matching posts with their reads, stars and revisions, duplicates of a removed post
+// become canonical themselves
+func (s *Store) deletePosts(match func(*database.Post) bool) int64 {
+	removed := make(map[uuid.UUID]bool)
+	s.posts = slices.DeleteFunc(s.posts, func(post *database.Post) bool {
+		if match(post) {
+			removed[post.ID] = true
+			return true
+		}
+		return false
+	})
+
+	for _, post := range s.posts {
+		if post.CanonicalPostID.Valid && removed[post.CanonicalPostID.UUID] {
+			post.CanonicalPostID = uuid.NullUUID{}
+		}
+	}
+	s.revisions = slices.DeleteFunc(s.revisions, func(revision database.PostRevision) bool {
+		return removed[revision.PostID]
+	})
+	for key := range s.reads {
+		if removed[key.postID] {
+			delete(s.reads, key)
+		}
+	}
+	for key := range s.stars {
+		if removed[key.postID] {
+			delete(s.stars, key)
+		}
+	}
+	return int64(len(removed))
+}
+
+func (s *Store) isStarred(postID uuid.UUID) bool {
+	for key := range s.stars {
+		if key.postID == postID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDuplicates reports whether other posts name postID as their canonical post
+func (s *Store) hasDuplicates(postID uuid.UUID) bool {
+	for _, post := range s.posts {
+		if post.CanonicalPostID.Valid && post.CanonicalPostID.UUID == postID {
+			return true
+		}
+	}
+	return false
+}
+
+// Users
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.ID == arg.ID {
+			return database.User{}, fmt.Errorf("a user with id %s already exists", arg.ID)
+		}
+		if user.UserName == arg.UserName {
+			return database.User{}, fmt.Errorf("a user with name %s already exists", arg.UserName)
+		}
+	}
+
+	user := database.User{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserName:  arg.UserName,
+	}
+	s.users = append(s.users, user)
+	return user, nil
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.users), nil
+}
+
+// DeleteUser removes every user, and through them every feed and follow
+func (s *Store) DeleteUser(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = nil
+	s.follows = nil
+	clear(s.reads)
+	clear(s.stars)
+	s.deleteFeeds(func(*database.Feed) bool { return true })
+	return nil
+}
+
+func (s *Store) GetUser(ctx context.Context, userName string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.UserName == userName {
+			return user, nil
+		}
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
+func (s *Store) GetUserByName(ctx context.Context, userName string) (uuid.UUID, error) {
+	user, err := s.GetUser(ctx, userName)
+	return user.ID, err
+}
+
+func (s *Store) GetUserNameById(ctx context.Context, id uuid.UUID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(id)
+	if user == nil {
+		return "", sql.ErrNoRows
+	}
+	return user.UserName, nil
+}
+
+// Feeds
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userByID(arg.UserID) == nil {
+		return database.Feed{}, fmt.Errorf("user %s doesn't exist", arg.UserID)
+	}
+	for _, feed := range s.feeds {
+		if feed.ID == arg.ID {
+			return database.Feed{}, fmt.Errorf("a feed with id %s already exists", arg.ID)
+		}
+		if feed.FeedUrl == arg.FeedUrl {
+			return database.Feed{}, fmt.Errorf("a feed with url %s already exists", arg.FeedUrl)
+		}
+	}
+
+	feed := &database.Feed{
+		ID:        arg.ID,
+		Createdat: arg.Createdat,
+		Updatedat: arg.Updatedat,
+		FeedName:  arg.FeedName,
+		FeedUrl:   arg.FeedUrl,
+		UserID:    arg.UserID,
+		Status:    "active",
+		SkipHours: []int32{},
+		SkipDays:  []string{},
+	}
+	s.feeds = append(s.feeds, feed)
+	return cloneFeed(feed), nil
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, feedUrl string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(feedUrl)
+	if feed == nil {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return feed.ID, nil
+}
+
+func (s *Store) GetFeedSchedule(ctx context.Context, feedUrl string) (database.GetFeedScheduleRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(feedUrl)
+	if feed == nil {
+		return database.GetFeedScheduleRow{}, sql.ErrNoRows
+	}
+	return database.GetFeedScheduleRow{ID: feed.ID, CronSchedule: feed.CronSchedule, QuietHours: feed.QuietHours}, nil
+}
+
+func (s *Store) GetFeedNameById(ctx context.Context, id uuid.UUID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByID(id)
+	if feed == nil {
+		return "", sql.ErrNoRows
+	}
+	return feed.FeedName, nil
+}
+
+func (s *Store) GetFeeds(ctx context.Context) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var feeds []database.Feed
+	for _, feed := range s.feeds {
+		feeds = append(feeds, cloneFeed(feed))
+	}
+	return feeds, nil
+}
+
+func (s *Store) GetFeedsByStatus(ctx context.Context, status string) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var feeds []database.Feed
+	for _, feed := range s.feeds {
+		if feed.Status == status {
+			feeds = append(feeds, cloneFeed(feed))
+		}
+	}
+	sort.SliceStable(feeds, func(i, j int) bool {
+		return feeds[i].Updatedat.After(feeds[j].Updatedat)
+	})
+	return feeds, nil
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByID(arg.ID)
+	if feed == nil || !feed.LeasedBy.Valid || feed.LeasedBy != arg.LeasedBy {
+		return 0, nil
+	}
+	feed.LastFetchedAt = now()
+	feed.NextFetchAt = arg.NextFetchAt
+	feed.FetchIntervalSeconds = arg.FetchIntervalSeconds
+	feed.LeasedBy = sql.NullString{}
+	feed.LeaseExpiresAt = sql.NullTime{}
+	feed.RefreshRequestedAt = sql.NullTime{}
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+// ClaimFeedsToFetch holds the store lock while it picks and leases the batch,
+// which is what FOR UPDATE SKIP LOCKED gives the Postgres query
+func (s *Store) ClaimFeedsToFetch(ctx context.Context, arg database.ClaimFeedsToFetchParams) ([]database.ClaimFeedsToFetchRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := time.Now()
+	passed := func(t sql.NullTime) bool {
+		return !t.Valid || !t.Time.After(current)
+	}
+
+	var due []*database.Feed
+	for _, feed := range s.feeds {
+		if feed.Status != "active" {
+			continue
+		}
+		if !passed(feed.NextFetchAt) && !feed.RefreshRequestedAt.Valid {
+			continue
+		}
+		if arg.OnlyRequested && !feed.RefreshRequestedAt.Valid {
+			continue
+		}
+		if !passed(feed.NotBefore) || !passed(feed.LeaseExpiresAt) {
+			continue
+		}
+		if notBefore, ok := s.hostBackoff[hostOf(feed.FeedUrl)]; ok && notBefore.After(current) {
+			continue
+		}
+		due = append(due, feed)
+	}
+
+	// refresh_requested_at ASC NULLS LAST, next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+	compare := func(a, b sql.NullTime, nullsFirst bool) int {
+		switch {
+		case a.Valid && b.Valid:
+			return a.Time.Compare(b.Time)
+		case a.Valid == b.Valid:
+			return 0
+		case a.Valid == nullsFirst:
+			return 1
+		default:
+			return -1
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *database.Feed) int {
+		if c := compare(a.RefreshRequestedAt, b.RefreshRequestedAt, false); c != 0 {
+			return c
+		}
+		if c := compare(a.NextFetchAt, b.NextFetchAt, true); c != 0 {
+			return c
+		}
+		return compare(a.LastFetchedAt, b.LastFetchedAt, true)
+	})
+	if len(due) > int(arg.BatchSize) {
+		due = due[:max(arg.BatchSize, 0)]
+	}
+
+	var claimed []database.ClaimFeedsToFetchRow
+	for _, feed := range due {
+		feed.LeasedBy = arg.Worker
+		feed.LeaseExpiresAt = arg.LeaseExpiresAt
+		claimed = append(claimed, database.ClaimFeedsToFetchRow{
+			ID:                   feed.ID,
+			FeedName:             feed.FeedName,
+			FeedUrl:              feed.FeedUrl,
+			LastFetchedAt:        feed.LastFetchedAt,
+			FetchIntervalSeconds: feed.FetchIntervalSeconds,
+			TtlMinutes:           feed.TtlMinutes,
+			SkipHours:            slices.Clone(feed.SkipHours),
+			SkipDays:             slices.Clone(feed.SkipDays),
+			UpdatePeriod:         feed.UpdatePeriod,
+			UpdateFrequency:      feed.UpdateFrequency,
+			CronSchedule:         feed.CronSchedule,
+			QuietHours:           feed.QuietHours,
+		})
+	}
+	return claimed, nil
+}
+
+func (s *Store) RecordFeedNotFound(ctx context.Context, id uuid.UUID) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByID(id)
+	if feed == nil {
+		return 0, sql.ErrNoRows
+	}
+	feed.ConsecutiveNotFound++
+	feed.Updatedat = time.Now()
+	return feed.ConsecutiveNotFound, nil
+}
+
+func (s *Store) ResetFeedNotFound(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(id); feed != nil {
+		feed.ConsecutiveNotFound = 0
+	}
+	return nil
+}
+
+func (s *Store) SetFeedStatus(ctx context.Context, arg database.SetFeedStatusParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.Status = arg.Status
+		feed.StatusReason = arg.StatusReason
+		feed.Updatedat = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) ReviveFeed(ctx context.Context, feedUrl string) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(feedUrl)
+	if feed == nil {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	feed.Status = "active"
+	feed.StatusReason = sql.NullString{}
+	feed.ConsecutiveNotFound = 0
+	feed.Updatedat = time.Now()
+	return cloneFeed(feed), nil
+}
+
+func (s *Store) SetFeedNextFetch(ctx context.Context, arg database.SetFeedNextFetchParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.NextFetchAt = arg.NextFetchAt
+		feed.Updatedat = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) SetFeedNotBefore(ctx context.Context, arg database.SetFeedNotBeforeParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.NotBefore = arg.NotBefore
+		feed.Updatedat = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) UpdateFeedHints(ctx context.Context, arg database.UpdateFeedHintsParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.TtlMinutes = arg.TtlMinutes
+		feed.SkipHours = slices.Clone(arg.SkipHours)
+		feed.SkipDays = slices.Clone(arg.SkipDays)
+		feed.UpdatePeriod = arg.UpdatePeriod
+		feed.UpdateFrequency = arg.UpdateFrequency
+	}
+	return nil
+}
+
+func (s *Store) SetFeedSchedule(ctx context.Context, arg database.SetFeedScheduleParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(arg.FeedUrl)
+	if feed == nil {
+		return 0, nil
+	}
+	if arg.CronSchedule.Valid {
+		feed.CronSchedule = arg.CronSchedule
+	}
+	if arg.QuietHours.Valid {
+		feed.QuietHours = arg.QuietHours
+	}
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+func (s *Store) ClearFeedSchedule(ctx context.Context, feedUrl string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(feedUrl)
+	if feed == nil {
+		return 0, nil
+	}
+	feed.CronSchedule = sql.NullString{}
+	feed.QuietHours = sql.NullString{}
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+func (s *Store) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(arg.FeedUrl)
+	if feed == nil {
+		return 0, nil
+	}
+	if arg.RetentionKeepLast.Valid {
+		feed.RetentionKeepLast = arg.RetentionKeepLast
+	}
+	if arg.RetentionMaxAgeDays.Valid {
+		feed.RetentionMaxAgeDays = arg.RetentionMaxAgeDays
+	}
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+func (s *Store) ClearFeedRetention(ctx context.Context, feedUrl string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(feedUrl)
+	if feed == nil {
+		return 0, nil
+	}
+	feed.RetentionKeepLast = sql.NullInt32{}
+	feed.RetentionMaxAgeDays = sql.NullInt32{}
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+func (s *Store) SetFeedExtractArticles(ctx context.Context, arg database.SetFeedExtractArticlesParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(arg.FeedUrl)
+	if feed == nil {
+		return 0, nil
+	}
+	feed.ExtractArticles = arg.ExtractArticles
+	feed.Updatedat = time.Now()
+	return 1, nil
+}
+
+func (s *Store) ReleaseFeedLeases(ctx context.Context, leasedBy sql.NullString) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !leasedBy.Valid {
+		return nil // leased_by = NULL matches nothing
+	}
+	for _, feed := range s.feeds {
+		if feed.LeasedBy == leasedBy {
+			feed.LeasedBy = sql.NullString{}
+			feed.LeaseExpiresAt = sql.NullTime{}
+		}
+	}
+	return nil
+}
+
+// requestRefresh marks the matching feeds as requested and returns how many there were
+func (s *Store) requestRefresh(match func(*database.Feed) bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows int64
+	for _, feed := range s.feeds {
+		if match(feed) {
+			feed.RefreshRequestedAt = now()
+			rows++
+		}
+	}
+	return rows
+}
+
+func (s *Store) RequestFeedRefresh(ctx context.Context, feedUrl string) (int64, error) {
+	return s.requestRefresh(func(feed *database.Feed) bool {
+		return feed.FeedUrl == feedUrl && feed.Status == "active"
+	}), nil
+}
+
+func (s *Store) RequestAllFeedsRefresh(ctx context.Context) (int64, error) {
+	return s.requestRefresh(func(feed *database.Feed) bool {
+		return feed.Status == "active"
+	}), nil
+}
+
+func (s *Store) RequestFollowedFeedsRefresh(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.requestRefresh(func(feed *database.Feed) bool {
+		return feed.Status == "active" && s.isFollowing(userID, feed.ID)
+	}), nil
+}
+
+func (s *Store) RecordFeedSuccess(ctx context.Context, arg database.RecordFeedSuccessParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.LastSuccessAt = now()
+		if arg.HadNewItems {
+			feed.LastNewItemAt = feed.LastSuccessAt
+		}
+	}
+	return nil
+}
+
+func (s *Store) RecordFeedError(ctx context.Context, arg database.RecordFeedErrorParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed := s.feedByID(arg.ID); feed != nil {
+		feed.LastError = arg.LastError
+		feed.LastErrorAt = now()
+	}
+	return nil
+}
+
+// Follows
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) ([]database.CreateFeedFollowRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.userByID(arg.UserID)
+	if user == nil {
+		return nil, fmt.Errorf("user %s doesn't exist", arg.UserID)
+	}
+	feed := s.feedByID(arg.FeedID)
+	if feed == nil {
+		return nil, fmt.Errorf("feed %s doesn't exist", arg.FeedID)
+	}
+	for _, follow := range s.follows {
+		if follow.ID == arg.ID {
+			return nil, fmt.Errorf("a feed follow with id %s already exists", arg.ID)
+		}
+	}
+
+	s.follows = append(s.follows, database.FeedFollow{
+		ID:        arg.ID,
+		Createdat: arg.Createdat,
+		Updatedat: arg.Updatedat,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+	})
+	return []database.CreateFeedFollowRow{{
+		ID:        arg.ID,
+		Createdat: arg.Createdat,
+		Updatedat: arg.Updatedat,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+		FeedName:  feed.FeedName,
+		UserName:  user.UserName,
+	}}, nil
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.GetFeedFollowsForUserRow
+	for _, follow := range s.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		user := s.userByID(follow.UserID)
+		feed := s.feedByID(follow.FeedID)
+		rows = append(rows, database.GetFeedFollowsForUserRow{
+			UserName: user.UserName,
+			FeedName: feed.FeedName,
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) DeleteFeedFollowRecord(ctx context.Context, arg database.DeleteFeedFollowRecordParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.feedByURL(arg.FeedUrl)
+	if feed == nil {
+		return nil
+	}
+	s.follows = slices.DeleteFunc(s.follows, func(follow database.FeedFollow) bool {
+		return follow.UserID == arg.UserID && follow.FeedID == feed.ID
+	})
+	return nil
+}
+
+// Fetch log
+
+func (s *Store) CreateFetchLog(ctx context.Context, arg database.CreateFetchLogParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.feedByID(arg.FeedID) == nil {
+		return fmt.Errorf("feed %s doesn't exist", arg.FeedID)
+	}
+	s.fetchLogs = append(s.fetchLogs, database.FetchLog(arg))
+	return nil
+}
+
+func (s *Store) GetFeedStats(ctx context.Context, arg database.GetFeedStatsParams) ([]database.GetFeedStatsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.GetFeedStatsRow
+	for _, feed := range s.feeds {
+		if arg.FeedUrl != "" && feed.FeedUrl != arg.FeedUrl {
+			continue
+		}
+
+		row := database.GetFeedStatsRow{ID: feed.ID, FeedName: feed.FeedName, FeedUrl: feed.FeedUrl}
+		var durations []int32
+		for _, entry := range s.fetchLogs {
+			if entry.FeedID != feed.ID || entry.StartedAt.Before(arg.Since) {
+				continue
+			}
+			row.Attempts++
+			if !entry.Error.Valid {
+				row.Successes++
+			}
+			row.NewItems += entry.NewItems
+			durations = append(durations, entry.DurationMs)
+		}
+
+		// percentile_cont(0.5) interpolates between the two middle values
+		slices.Sort(durations)
+		if n := len(durations); n > 0 {
+			row.MedianDurationMs = float64(durations[(n-1)/2]+durations[n/2]) / 2
+		}
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].FeedName < rows[j].FeedName
+	})
+	return rows, nil
+}
+
+// Host backoff and agg workers
+
+func (s *Store) SetHostBackoff(ctx context.Context, arg database.SetHostBackoffParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.hostBackoff[arg.Host]; !ok || arg.NotBefore.After(current) {
+		s.hostBackoff[arg.Host] = arg.NotBefore
+	}
+	return nil
+}
+
+func (s *Store) UpsertAggWorker(ctx context.Context, workerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	worker, ok := s.workers[workerID]
+	if !ok {
+		worker = database.AggWorker{WorkerID: workerID, StartedAt: time.Now()}
+	}
+	worker.LastSeenAt = time.Now()
+	s.workers[workerID] = worker
+	return nil
+}
+
+func (s *Store) DeleteAggWorker(ctx context.Context, workerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.workers, workerID)
+	return nil
+}
+
+func (s *Store) CountLiveAggWorkers(ctx context.Context, lastSeenAt time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, worker := range s.workers {
+		if worker.LastSeenAt.After(lastSeenAt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Posts
+
+func (s *Store) CreatePost(ctx context.Context, arg database.CreatePostParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.feedByID(arg.FeedID) == nil {
+		return 0, fmt.Errorf("feed %s doesn't exist", arg.FeedID)
+	}
+	if arg.CanonicalPostID.Valid && s.postByID(arg.CanonicalPostID.UUID) == nil {
+		return 0, fmt.Errorf("post %s doesn't exist", arg.CanonicalPostID.UUID)
+	}
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID && post.Url == arg.Url {
+			return 0, nil // ON CONFLICT (feed_id, url) DO NOTHING
+		}
+		if post.ID == arg.ID {
+			return 0, fmt.Errorf("a post with id %s already exists", arg.ID)
+		}
+	}
+
+	s.posts = append(s.posts, &database.Post{
+		ID:              arg.ID,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+		Title:           arg.Title,
+		Url:             arg.Url,
+		Description:     arg.Description,
+		PublishedAt:     arg.PublishedAt,
+		FeedID:          arg.FeedID,
+		CanonicalUrl:    arg.CanonicalUrl,
+		Fingerprint:     arg.Fingerprint,
+		CanonicalPostID: arg.CanonicalPostID,
+		Content:         arg.Content,
+		Author:          arg.Author,
+		Guid:            arg.Guid,
+	})
+	return 1, nil
+}
+
+func (s *Store) GetPost(ctx context.Context, id uuid.UUID) (database.GetPostRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post := s.postByID(id)
+	if post == nil {
+		return database.GetPostRow{}, sql.ErrNoRows
+	}
+	return database.GetPostRow{
+		ID:               post.ID,
+		Title:            post.Title,
+		Url:              post.Url,
+		Description:      post.Description,
+		Content:          post.Content,
+		CreatedAt:        post.CreatedAt,
+		UpdatedAt:        post.UpdatedAt,
+		ArticleHtml:      post.ArticleHtml,
+		ArticleText:      post.ArticleText,
+		ArticleFetchedAt: post.ArticleFetchedAt,
+		ArticleError:     post.ArticleError,
+		FeedName:         s.feedByID(post.FeedID).FeedName,
+	}, nil
+}
+
+func (s *Store) GetPostByGUID(ctx context.Context, arg database.GetPostByGUIDParams) (database.GetPostByGUIDRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *database.Post
+	for _, post := range s.posts {
+		if !arg.Guid.Valid || post.FeedID != arg.FeedID || post.Guid != arg.Guid {
+			continue
+		}
+		if found == nil || post.CreatedAt.Before(found.CreatedAt) {
+			found = post
+		}
+	}
+
+	if found == nil {
+		return database.GetPostByGUIDRow{}, sql.ErrNoRows
+	}
+	return database.GetPostByGUIDRow{
+		ID:          found.ID,
+		Title:       found.Title,
+		Description: found.Description,
+		Content:     found.Content,
+	}, nil
+}
+
+func (s *Store) SetPostGUID(ctx context.Context, arg database.SetPostGUIDParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID && post.Url == arg.Url && !post.Guid.Valid {
+			post.Guid = arg.Guid
+		}
+	}
+	return nil
+}
+
+func (s *Store) UpdatePostContent(ctx context.Context, arg database.UpdatePostContentParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if post := s.postByID(arg.ID); post != nil {
+		post.Title = arg.Title
+		post.Description = arg.Description
+		post.Content = arg.Content
+		post.Fingerprint = arg.Fingerprint
+		post.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *Store) GetRecentPublishTimes(ctx context.Context, arg database.GetRecentPublishTimesParams) ([]sql.NullTime, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var published []sql.NullTime
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID && post.PublishedAt.Valid {
+			published = append(published, post.PublishedAt)
+		}
+	}
+	slices.SortFunc(published, func(a, b sql.NullTime) int {
+		return b.Time.Compare(a.Time)
+	})
+	if len(published) > int(arg.HistoryLimit) {
+		published = published[:max(arg.HistoryLimit, 0)]
+	}
+	return published, nil
+}
+
+func (s *Store) GetPostsToExtract(ctx context.Context, limit int32) ([]database.GetPostsToExtractRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var posts []*database.Post
+	for _, post := range s.posts {
+		feed := s.feedByID(post.FeedID)
+		if !feed.ExtractArticles || feed.Status != "active" || post.ArticleFetchedAt.Valid || post.CanonicalPostID.Valid {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	slices.SortStableFunc(posts, func(a, b *database.Post) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(posts) > int(limit) {
+		posts = posts[:max(limit, 0)]
+	}
+
+	var rows []database.GetPostsToExtractRow
+	for _, post := range posts {
+		rows = append(rows, database.GetPostsToExtractRow{
+			ID:      post.ID,
+			Url:     post.Url,
+			FeedUrl: s.feedByID(post.FeedID).FeedUrl,
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) SaveArticle(ctx context.Context, arg database.SaveArticleParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if post := s.postByID(arg.ID); post != nil {
+		post.ArticleHtml = arg.ArticleHtml
+		post.ArticleText = arg.ArticleText
+		post.ArticleFetchedAt = now()
+		post.ArticleError = sql.NullString{}
+	}
+	return nil
+}
+
+func (s *Store) RecordArticleError(ctx context.Context, arg database.RecordArticleErrorParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if post := s.postByID(arg.ID); post != nil {
+		post.ArticleFetchedAt = now()
+		post.ArticleError = arg.ArticleError
+	}
+	return nil
+}
+
+func (s *Store) FindCanonicalPost(ctx context.Context, arg database.FindCanonicalPostParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *database.Post
+	for _, post := range s.posts {
+		if post.CanonicalPostID.Valid || post.FeedID == arg.FeedID {
+			continue
+		}
+		sameURL := post.CanonicalUrl == arg.CanonicalUrl
+		sameContent := arg.Fingerprint != "" && post.Fingerprint == arg.Fingerprint
+		if !sameURL && !sameContent {
+			continue
+		}
+		if found == nil || post.CreatedAt.Before(found.CreatedAt) {
+			found = post
+		}
+	}
+
+	if found == nil {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return found.ID, nil
+}
+
+// newestFirst orders posts by published_at DESC NULLS LAST, created_at DESC
+func newestFirst(a, b *database.Post) int {
+	switch {
+	case a.PublishedAt.Valid && b.PublishedAt.Valid:
+		if c := b.PublishedAt.Time.Compare(a.PublishedAt.Time); c != 0 {
+			return c
+		}
+	case a.PublishedAt.Valid:
+		return -1
+	case b.PublishedAt.Valid:
+		return 1
+	}
+	return b.CreatedAt.Compare(a.CreatedAt)
+}
+
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var visible []*database.Post
+	for _, post := range s.posts {
+		if post.CanonicalPostID.Valid {
+			continue
+		}
+		_, read := s.reads[postKey{arg.UserID, post.ID}]
+		if read && !arg.IncludeRead {
+			continue
+		}
+
+		followed := s.isFollowing(arg.UserID, post.FeedID)
+		for _, duplicate := range s.posts {
+			if duplicate.CanonicalPostID == (uuid.NullUUID{UUID: post.ID, Valid: true}) && s.isFollowing(arg.UserID, duplicate.FeedID) {
+				followed = true
+			}
+		}
+		if followed {
+			visible = append(visible, post)
+		}
+	}
+
+	slices.SortStableFunc(visible, newestFirst)
+	if len(visible) > int(arg.PostLimit) {
+		visible = visible[:max(arg.PostLimit, 0)]
+	}
+
+	var rows []database.GetPostsForUserRow
+	for _, post := range visible {
+		var alsoIn []string
+		for _, duplicate := range s.posts {
+			if duplicate.CanonicalPostID == (uuid.NullUUID{UUID: post.ID, Valid: true}) {
+				alsoIn = append(alsoIn, s.feedByID(duplicate.FeedID).FeedName)
+			}
+		}
+		slices.Sort(alsoIn)
+
+		_, read := s.reads[postKey{arg.UserID, post.ID}]
+		rows = append(rows, database.GetPostsForUserRow{
+			ID:          post.ID,
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedName:    s.feedByID(post.FeedID).FeedName,
+			AlsoIn:      strings.Join(alsoIn, ", "),
+			IsRead:      read,
+		})
+	}
+	return rows, nil
+}
+
+// SearchPosts evaluates the tsquery with search.Matcher. Words are not stemmed,
+// and a title hit ranks above a description hit, which ranks above a content hit.
+func (s *Store) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	matcher, err := search.NewMatcher(arg.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type result struct {
+		post *database.Post
+		row  database.SearchPostsRow
+	}
+	var results []result
+	for _, post := range s.posts {
+		feed := s.feedByID(post.FeedID)
+		if arg.FeedUrl != "" && feed.FeedUrl != arg.FeedUrl {
+			continue
+		}
+		if arg.FeedUrl == "" && post.CanonicalPostID.Valid {
+			continue
+		}
+		if arg.Since.Valid && (!post.PublishedAt.Valid || post.PublishedAt.Time.Before(arg.Since.Time)) {
+			continue
+		}
+		if arg.Until.Valid && (!post.PublishedAt.Valid || !post.PublishedAt.Time.Before(arg.Until.Time)) {
+			continue
+		}
+		if arg.Author != "" && !strings.Contains(strings.ToLower(post.Author.String), strings.ToLower(arg.Author)) {
+			continue
+		}
+		if !matcher.Match(post.Title, post.Description.String, post.Content.String, post.ArticleText.String) {
+			continue
+		}
+
+		rank := float32(matcher.Hits(post.Title)) +
+			0.4*float32(matcher.Hits(post.Description.String)) +
+			0.2*float32(matcher.Hits(post.Content.String)) +
+			0.1*float32(matcher.Hits(post.ArticleText.String))
+		snippetSource := post.Description.String
+		if !post.Description.Valid {
+			snippetSource = post.Content.String
+		}
+		if !post.Description.Valid && !post.Content.Valid {
+			snippetSource = post.ArticleText.String
+		}
+
+		results = append(results, result{post: post, row: database.SearchPostsRow{
+			ID:          post.ID,
+			Title:       post.Title,
+			Url:         post.Url,
+			PublishedAt: post.PublishedAt,
+			Author:      post.Author,
+			FeedName:    feed.FeedName,
+			Rank:        rank,
+			Snippet:     matcher.Highlight(snippetSource, 30),
+		}})
+	}
+
+	slices.SortStableFunc(results, func(a, b result) int {
+		if a.row.Rank != b.row.Rank {
+			if a.row.Rank > b.row.Rank {
+				return -1
+			}
+			return 1
+		}
+		return newestFirst(a.post, b.post)
+	})
+	if len(results) > int(arg.ResultLimit) {
+		results = results[:max(arg.ResultLimit, 0)]
+	}
+
+	var rows []database.SearchPostsRow
+	for _, r := range results {
+		rows = append(rows, r.row)
+	}
+	return rows, nil
+}
+
+// GetPrunablePosts applies the same rules as the Postgres query: outside the newest keep_last posts
+// of the feed or older than max_age_days, a limit of 0 is no limit, starred posts are kept
+func (s *Store) GetPrunablePosts(ctx context.Context, arg database.GetPrunablePostsParams) ([]database.GetPrunablePostsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	postedAt := func(post *database.Post) time.Time {
+		if post.PublishedAt.Valid {
+			return post.PublishedAt.Time
+		}
+		return post.CreatedAt
+	}
+
+	type prunable struct {
+		row      database.GetPrunablePostsRow
+		postedAt time.Time
+	}
+	var found []prunable
+	for _, feed := range s.feeds {
+		keepLast := arg.DefaultKeepLast
+		if feed.RetentionKeepLast.Valid {
+			keepLast = feed.RetentionKeepLast.Int32
+		}
+		maxAgeDays := arg.DefaultMaxAgeDays
+		if feed.RetentionMaxAgeDays.Valid {
+			maxAgeDays = feed.RetentionMaxAgeDays.Int32
+		}
+
+		var posts []*database.Post
+		for _, post := range s.posts {
+			if post.FeedID == feed.ID {
+				posts = append(posts, post)
+			}
+		}
+		slices.SortStableFunc(posts, func(a, b *database.Post) int {
+			if c := postedAt(b).Compare(postedAt(a)); c != 0 {
+				return c
+			}
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+
+		cutoff := time.Now().AddDate(0, 0, -int(maxAgeDays))
+		for i, post := range posts {
+			tooMany := keepLast > 0 && i+1 > int(keepLast)
+			tooOld := maxAgeDays > 0 && postedAt(post).Before(cutoff)
+			if (tooMany || tooOld) && !s.isStarred(post.ID) && !s.hasDuplicates(post.ID) {
+				found = append(found, prunable{
+					row:      database.GetPrunablePostsRow{ID: post.ID, Title: post.Title, FeedName: feed.FeedName},
+					postedAt: postedAt(post),
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(found, func(a, b prunable) int {
+		if c := strings.Compare(a.row.FeedName, b.row.FeedName); c != 0 {
+			return c
+		}
+		return a.postedAt.Compare(b.postedAt)
+	})
+
+	var rows []database.GetPrunablePostsRow
+	for _, p := range found {
+		rows = append(rows, p.row)
+	}
+	return rows, nil
+}
+
+func (s *Store) DeletePosts(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		deleted[id] = !s.isStarred(id) && !s.hasDuplicates(id)
+	}
+	return s.deletePosts(func(post *database.Post) bool {
+		return deleted[post.ID]
+	}), nil
+}
+
+func (s *Store) RecordPrunedPosts(ctx context.Context, ids []uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		post := s.postByID(id)
+		if post == nil || s.isStarred(post.ID) || s.hasDuplicates(post.ID) {
+			continue
+		}
+		s.pruned = slices.DeleteFunc(s.pruned, func(pruned database.PrunedPost) bool {
+			return pruned.FeedID == post.FeedID && pruned.Url == post.Url
+		})
+		s.pruned = append(s.pruned, database.PrunedPost{
+			FeedID:   post.FeedID,
+			Url:      post.Url,
+			Guid:     post.Guid,
+			PrunedAt: time.Now(),
+		})
+	}
+	return nil
+}
+
+func (s *Store) IsPostPruned(ctx context.Context, arg database.IsPostPrunedParams) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pruned := range s.pruned {
+		if pruned.FeedID != arg.FeedID {
+			continue
+		}
+		if pruned.Url == arg.Url || (arg.Guid != "" && pruned.Guid.String == arg.Guid) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Revisions
+
+func (s *Store) CreatePostRevision(ctx context.Context, arg database.CreatePostRevisionParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.postByID(arg.PostID) == nil {
+		return fmt.Errorf("post %s doesn't exist", arg.PostID)
+	}
+	for _, revision := range s.revisions {
+		if revision.ID == arg.ID {
+			return fmt.Errorf("a post revision with id %s already exists", arg.ID)
+		}
+	}
+	s.revisions = append(s.revisions, database.PostRevision(arg))
+	return nil
+}
+
+func (s *Store) GetPostRevisions(ctx context.Context, postID uuid.UUID) ([]database.GetPostRevisionsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.GetPostRevisionsRow
+	for _, revision := range s.revisions {
+		if revision.PostID == postID {
+			rows = append(rows, database.GetPostRevisionsRow{
+				ID:          revision.ID,
+				RevisedAt:   revision.RevisedAt,
+				Title:       revision.Title,
+				Description: revision.Description,
+				Content:     revision.Content,
+			})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].RevisedAt.Before(rows[j].RevisedAt)
+	})
+	return rows, nil
+}
+
+// Read state and stars
+
+// toggle adds or removes the user's row for the canonical copy of postID in rows and reports whether it changed
+func (s *Store) toggle(rows map[postKey]time.Time, userID, postID uuid.UUID, set bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post := s.postByID(postID)
+	if post == nil {
+		return 0
+	}
+	key := postKey{userID, canonicalID(post)}
+	_, exists := rows[key]
+	if exists == set {
+		return 0
+	}
+	if set {
+		rows[key] = time.Now()
+	} else {
+		delete(rows, key)
+	}
+	return 1
+}
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) (int64, error) {
+	return s.toggle(s.reads, arg.UserID, arg.PostID, true), nil
+}
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) (int64, error) {
+	return s.toggle(s.reads, arg.UserID, arg.PostID, false), nil
+}
+
+func (s *Store) MarkPostsReadBefore(ctx context.Context, arg database.MarkPostsReadBeforeParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows int64
+	for _, post := range s.posts {
+		postedAt := post.CreatedAt
+		if post.PublishedAt.Valid {
+			postedAt = post.PublishedAt.Time
+		}
+		if !postedAt.Before(arg.Before) {
+			continue
+		}
+
+		if arg.FeedUrl != "" {
+			if s.feedByID(post.FeedID).FeedUrl != arg.FeedUrl {
+				continue
+			}
+		} else if !s.isFollowing(arg.UserID, post.FeedID) {
+			continue
+		}
+
+		key := postKey{arg.UserID, canonicalID(post)}
+		if _, read := s.reads[key]; !read {
+			s.reads[key] = time.Now()
+			rows++
+		}
+	}
+	return rows, nil
+}
+
+func (s *Store) StarPost(ctx context.Context, arg database.StarPostParams) (int64, error) {
+	return s.toggle(s.stars, arg.UserID, arg.PostID, true), nil
+}
+
+func (s *Store) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	return s.toggle(s.stars, arg.UserID, arg.PostID, false), nil
+}
+
+func (s *Store) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []database.GetStarredPostsRow
+	for key, starredAt := range s.stars {
+		if key.userID != userID {
+			continue
+		}
+		post := s.postByID(key.postID)
+		rows = append(rows, database.GetStarredPostsRow{
+			ID:          post.ID,
+			Title:       post.Title,
+			Url:         post.Url,
+			PublishedAt: post.PublishedAt,
+			FeedName:    s.feedByID(post.FeedID).FeedName,
+			StarredAt:   starredAt,
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].StarredAt.After(rows[j].StarredAt)
+	})
+	return rows, nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+func newFeed(t *testing.T, s *Store, userID uuid.UUID, feedURL string) database.Feed {
+	t.Helper()
+	feed, err := s.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID: uuid.New(), Createdat: time.Now(), Updatedat: time.Now(),
+		FeedName: feedURL, FeedUrl: feedURL, UserID: userID,
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	return feed
+}
+
+func newPost(t *testing.T, s *Store, feedID uuid.UUID, postURL string, canonical uuid.NullUUID) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	created, err := s.CreatePost(context.Background(), database.CreatePostParams{
+		ID: id, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		Title: postURL, Url: postURL, FeedID: feedID, CanonicalUrl: postURL, CanonicalPostID: canonical,
+	})
+	if err != nil || created != 1 {
+		t.Fatalf("CreatePost() = %d, %v", created, err)
+	}
+	return id
+}
+
+func TestStore_ClaimFeedsToFetch(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	user, _ := s.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), UserName: "alice"})
+	newFeed(t, s, user.ID, "https://a.example/rss")
+	newFeed(t, s, user.ID, "https://b.example/rss")
+
+	claim := func(worker string) []database.ClaimFeedsToFetchRow {
+		rows, err := s.ClaimFeedsToFetch(ctx, database.ClaimFeedsToFetchParams{
+			Worker:         sql.NullString{String: worker, Valid: true},
+			LeaseExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+			BatchSize:      1,
+		})
+		if err != nil {
+			t.Fatalf("ClaimFeedsToFetch() error = %v", err)
+		}
+		return rows
+	}
+
+	first, second := claim("one"), claim("two")
+	if len(first) != 1 || len(second) != 1 || first[0].ID == second[0].ID {
+		t.Fatalf("two workers claimed %v and %v, want one different feed each", first, second)
+	}
+	if rows := claim("three"); len(rows) != 0 {
+		t.Errorf("leased feeds were claimed again: %v", rows)
+	}
+
+	if err := s.ReleaseFeedLeases(ctx, sql.NullString{String: "one", Valid: true}); err != nil {
+		t.Fatalf("ReleaseFeedLeases() error = %v", err)
+	}
+	if rows := claim("three"); len(rows) != 1 || rows[0].ID != first[0].ID {
+		t.Errorf("after releasing, claimed %v, want %s", rows, first[0].FeedUrl)
+	}
+}
+
+func TestStore_duplicatesAndStars(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	user, _ := s.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), UserName: "alice"})
+	a := newFeed(t, s, user.ID, "https://a.example/rss")
+	b := newFeed(t, s, user.ID, "https://b.example/rss")
+	if _, err := s.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: user.ID, FeedID: b.ID}); err != nil {
+		t.Fatalf("CreateFeedFollow() error = %v", err)
+	}
+
+	original := newPost(t, s, a.ID, "https://a.example/story", uuid.NullUUID{})
+	duplicate := newPost(t, s, b.ID, "https://b.example/story", uuid.NullUUID{UUID: original, Valid: true})
+
+	// The story only reached a followed feed as a duplicate, it is still shown once under the original
+	posts, err := s.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForUser() error = %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != original || posts[0].AlsoIn != b.FeedName {
+		t.Fatalf("GetPostsForUser() = %+v, want the original post also in %s", posts, b.FeedName)
+	}
+
+	// Reading or starring the duplicate applies to the original
+	if rows, _ := s.MarkPostRead(ctx, database.MarkPostReadParams{UserID: user.ID, PostID: duplicate}); rows != 1 {
+		t.Errorf("MarkPostRead() = %d, want 1", rows)
+	}
+	posts, _ = s.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if len(posts) != 0 {
+		t.Errorf("GetPostsForUser() after reading = %+v, want no unread posts", posts)
+	}
+	if rows, _ := s.StarPost(ctx, database.StarPostParams{UserID: user.ID, PostID: duplicate}); rows != 1 {
+		t.Errorf("StarPost() = %d, want 1", rows)
+	}
+
+	deleted, err := s.DeletePosts(ctx, []uuid.UUID{original, duplicate})
+	if err != nil {
+		t.Fatalf("DeletePosts() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeletePosts() = %d, want only the unstarred duplicate deleted", deleted)
+	}
+	starred, _ := s.GetStarredPosts(ctx, user.ID)
+	if len(starred) != 1 || starred[0].ID != original {
+		t.Errorf("GetStarredPosts() = %+v, want the original post", starred)
+	}
+}
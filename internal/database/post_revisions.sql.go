@@ -0,0 +1,83 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: post_revisions.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createPostRevision = `-- name: CreatePostRevision :exec
+INSERT INTO post_revisions(id, post_id, revised_at, title, description, content)
+VALUES($1, $2, $3, $4, $5, $6)
+`
+
+type CreatePostRevisionParams struct {
+	ID          uuid.UUID
+	PostID      uuid.UUID
+	RevisedAt   time.Time
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+}
+
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) error {
+	_, err := q.db.ExecContext(ctx, createPostRevision,
+		arg.ID,
+		arg.PostID,
+		arg.RevisedAt,
+		arg.Title,
+		arg.Description,
+		arg.Content,
+	)
+	return err
+}
+
+const getPostRevisions = `-- name: GetPostRevisions :many
+SELECT id, revised_at, title, description, content FROM post_revisions
+WHERE post_id = $1
+ORDER BY revised_at
+`
+
+type GetPostRevisionsRow struct {
+	ID          uuid.UUID
+	RevisedAt   time.Time
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+}
+
+func (q *Queries) GetPostRevisions(ctx context.Context, postID uuid.UUID) ([]GetPostRevisionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostRevisions, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostRevisionsRow
+	for rows.Next() {
+		var i GetPostRevisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RevisedAt,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
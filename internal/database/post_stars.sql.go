@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: post_stars.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getStarredPosts = `-- name: GetStarredPosts :many
+SELECT posts.id, posts.title, posts.url, posts.published_at, feeds.feed_name, post_stars.starred_at
+    FROM post_stars
+    INNER JOIN posts ON post_stars.post_id = posts.id
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE post_stars.user_id = $1
+    ORDER BY post_stars.starred_at DESC
+`
+
+type GetStarredPostsRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	PublishedAt sql.NullTime
+	FeedName    string
+	StarredAt   time.Time
+}
+
+func (q *Queries) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]GetStarredPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredPosts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStarredPostsRow
+	for rows.Next() {
+		var i GetStarredPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.StarredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const starPost = `-- name: StarPost :execrows
+INSERT INTO post_stars(user_id, post_id, starred_at)
+SELECT $1::uuid, COALESCE(posts.canonical_post_id, posts.id), NOW()
+    FROM posts WHERE posts.id = $2
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type StarPostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) StarPost(ctx context.Context, arg StarPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, starPost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unstarPost = `-- name: UnstarPost :execrows
+DELETE FROM post_stars
+    WHERE user_id = $1
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = $2)
+`
+
+type UnstarPostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) UnstarPost(ctx context.Context, arg UnstarPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unstarPost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
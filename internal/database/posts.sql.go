@@ -0,0 +1,545 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: posts.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const createPost = `-- name: CreatePost :execrows
+INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id, canonical_url, fingerprint, canonical_post_id, content, author, guid)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (feed_id, url) DO NOTHING
+`
+
+type CreatePostParams struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Title           string
+	Url             string
+	Description     sql.NullString
+	PublishedAt     sql.NullTime
+	FeedID          uuid.UUID
+	CanonicalUrl    string
+	Fingerprint     string
+	CanonicalPostID uuid.NullUUID
+	Content         sql.NullString
+	Author          sql.NullString
+	Guid            sql.NullString
+}
+
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.PublishedAt,
+		arg.FeedID,
+		arg.CanonicalUrl,
+		arg.Fingerprint,
+		arg.CanonicalPostID,
+		arg.Content,
+		arg.Author,
+		arg.Guid,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePosts = `-- name: DeletePosts :execrows
+DELETE FROM posts
+    WHERE id = ANY($1::uuid[])
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id)
+`
+
+func (q *Queries) DeletePosts(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePosts, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const findCanonicalPost = `-- name: FindCanonicalPost :one
+SELECT id FROM posts
+WHERE canonical_post_id IS NULL
+AND feed_id <> $1
+AND (canonical_url = $2 OR ($3::text <> '' AND fingerprint = $3::text))
+ORDER BY created_at
+LIMIT 1
+`
+
+type FindCanonicalPostParams struct {
+	FeedID       uuid.UUID
+	CanonicalUrl string
+	Fingerprint  string
+}
+
+func (q *Queries) FindCanonicalPost(ctx context.Context, arg FindCanonicalPostParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, findCanonicalPost, arg.FeedID, arg.CanonicalUrl, arg.Fingerprint)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getPost = `-- name: GetPost :one
+SELECT posts.id, posts.title, posts.url, posts.description, posts.content, posts.created_at, posts.updated_at,
+    posts.article_html, posts.article_text, posts.article_fetched_at, posts.article_error,
+    feeds.feed_name
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.id = $1
+`
+
+type GetPostRow struct {
+	ID               uuid.UUID
+	Title            string
+	Url              string
+	Description      sql.NullString
+	Content          sql.NullString
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	ArticleHtml      sql.NullString
+	ArticleText      sql.NullString
+	ArticleFetchedAt sql.NullTime
+	ArticleError     sql.NullString
+	FeedName         string
+}
+
+func (q *Queries) GetPost(ctx context.Context, id uuid.UUID) (GetPostRow, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
+	var i GetPostRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ArticleHtml,
+		&i.ArticleText,
+		&i.ArticleFetchedAt,
+		&i.ArticleError,
+		&i.FeedName,
+	)
+	return i, err
+}
+
+const getPostByGUID = `-- name: GetPostByGUID :one
+SELECT id, title, description, content FROM posts
+WHERE feed_id = $1 AND guid = $2
+ORDER BY created_at
+LIMIT 1
+`
+
+type GetPostByGUIDParams struct {
+	FeedID uuid.UUID
+	Guid   sql.NullString
+}
+
+type GetPostByGUIDRow struct {
+	ID          uuid.UUID
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+}
+
+func (q *Queries) GetPostByGUID(ctx context.Context, arg GetPostByGUIDParams) (GetPostByGUIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostByGUID, arg.FeedID, arg.Guid)
+	var i GetPostByGUIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+	)
+	return i, err
+}
+
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT
+    posts.id, posts.title, posts.url, posts.description, posts.published_at,
+    feeds.feed_name,
+    COALESCE((
+        SELECT string_agg(duplicate_feeds.feed_name, ', ' ORDER BY duplicate_feeds.feed_name)
+        FROM posts AS duplicates
+        INNER JOIN feeds AS duplicate_feeds ON duplicates.feed_id = duplicate_feeds.id
+        WHERE duplicates.canonical_post_id = posts.id
+    ), '')::text AS also_in,
+    EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id AND post_reads.user_id = $1
+    ) AS is_read
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.canonical_post_id IS NULL
+    AND ($2::boolean OR NOT EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id AND post_reads.user_id = $1
+    ))
+    AND EXISTS (
+        SELECT 1 FROM feed_follow
+        WHERE feed_follow.user_id = $1
+        AND (feed_follow.feed_id = posts.feed_id
+            OR feed_follow.feed_id IN (SELECT duplicates.feed_id FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id))
+    )
+    ORDER BY posts.published_at DESC NULLS LAST, posts.created_at DESC
+    LIMIT $3
+`
+
+type GetPostsForUserParams struct {
+	UserID      uuid.UUID
+	IncludeRead bool
+	PostLimit   int32
+}
+
+type GetPostsForUserRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	FeedName    string
+	AlsoIn      string
+	IsRead      bool
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.IncludeRead, arg.PostLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.AlsoIn,
+			&i.IsRead,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsToExtract = `-- name: GetPostsToExtract :many
+SELECT posts.id, posts.url, feeds.feed_url
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE feeds.extract_articles
+    AND feeds.status = 'active'
+    AND posts.article_fetched_at IS NULL
+    AND posts.canonical_post_id IS NULL
+    ORDER BY posts.created_at DESC
+    LIMIT $1
+`
+
+type GetPostsToExtractRow struct {
+	ID      uuid.UUID
+	Url     string
+	FeedUrl string
+}
+
+// Newest first, so a feed that was just switched on gets its recent posts before its backlog
+func (q *Queries) GetPostsToExtract(ctx context.Context, limit int32) ([]GetPostsToExtractRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsToExtract, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsToExtractRow
+	for rows.Next() {
+		var i GetPostsToExtractRow
+		if err := rows.Scan(&i.ID, &i.Url, &i.FeedUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPrunablePosts = `-- name: GetPrunablePosts :many
+WITH ranked AS (
+    SELECT
+        posts.id, posts.title, feeds.feed_name,
+        COALESCE(posts.published_at, posts.created_at) AS posted_at,
+        row_number() OVER (
+            PARTITION BY posts.feed_id
+            ORDER BY COALESCE(posts.published_at, posts.created_at) DESC, posts.created_at DESC
+        ) AS position,
+        COALESCE(feeds.retention_keep_last, $1::int) AS keep_last,
+        COALESCE(feeds.retention_max_age_days, $2::int) AS max_age_days
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+)
+SELECT ranked.id, ranked.title, ranked.feed_name
+    FROM ranked
+    WHERE (
+        (ranked.keep_last > 0 AND ranked.position > ranked.keep_last)
+        OR (ranked.max_age_days > 0 AND ranked.posted_at < NOW() - make_interval(days => ranked.max_age_days))
+    )
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = ranked.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = ranked.id)
+    ORDER BY ranked.feed_name, ranked.posted_at
+`
+
+type GetPrunablePostsParams struct {
+	DefaultKeepLast   int32
+	DefaultMaxAgeDays int32
+}
+
+type GetPrunablePostsRow struct {
+	ID       uuid.UUID
+	Title    string
+	FeedName string
+}
+
+// A post is pruned when it falls outside the newest keep_last posts of its feed or is older than max_age_days,
+// a limit of 0 is no limit. Starred posts are always kept, and so is a post that duplicates still point at,
+// it holds the read state of the story.
+func (q *Queries) GetPrunablePosts(ctx context.Context, arg GetPrunablePostsParams) ([]GetPrunablePostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPrunablePosts, arg.DefaultKeepLast, arg.DefaultMaxAgeDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPrunablePostsRow
+	for rows.Next() {
+		var i GetPrunablePostsRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentPublishTimes = `-- name: GetRecentPublishTimes :many
+SELECT published_at FROM posts
+    WHERE feed_id = $1
+    AND published_at IS NOT NULL
+    ORDER BY published_at DESC
+    LIMIT $2
+`
+
+type GetRecentPublishTimesParams struct {
+	FeedID       uuid.UUID
+	HistoryLimit int32
+}
+
+// Newest first, the publish history agg estimates a feed's posting rate from
+func (q *Queries) GetRecentPublishTimes(ctx context.Context, arg GetRecentPublishTimesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPublishTimes, arg.FeedID, arg.HistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var published_at sql.NullTime
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordArticleError = `-- name: RecordArticleError :exec
+UPDATE posts SET article_fetched_at = NOW(), article_error = $2
+WHERE id = $1
+`
+
+type RecordArticleErrorParams struct {
+	ID           uuid.UUID
+	ArticleError sql.NullString
+}
+
+// The post isn't tried again by agg, the article command can still fetch it
+func (q *Queries) RecordArticleError(ctx context.Context, arg RecordArticleErrorParams) error {
+	_, err := q.db.ExecContext(ctx, recordArticleError, arg.ID, arg.ArticleError)
+	return err
+}
+
+const saveArticle = `-- name: SaveArticle :exec
+UPDATE posts SET article_html = $2, article_text = $3, article_fetched_at = NOW(), article_error = NULL
+WHERE id = $1
+`
+
+type SaveArticleParams struct {
+	ID          uuid.UUID
+	ArticleHtml sql.NullString
+	ArticleText sql.NullString
+}
+
+func (q *Queries) SaveArticle(ctx context.Context, arg SaveArticleParams) error {
+	_, err := q.db.ExecContext(ctx, saveArticle, arg.ID, arg.ArticleHtml, arg.ArticleText)
+	return err
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT
+    posts.id, posts.title, posts.url, posts.published_at, posts.author,
+    feeds.feed_name,
+    ts_rank(posts.search_vector, to_tsquery('english', $1::text))::float4 AS rank,
+    ts_headline('english', coalesce(posts.description, posts.content, posts.article_text, ''), to_tsquery('english', $1::text),
+        'MaxFragments=1, MaxWords=30, MinWords=10, StartSel=**, StopSel=**')::text AS snippet
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.search_vector @@ to_tsquery('english', $1::text)
+    AND ($2::text = '' OR feeds.feed_url = $2::text)
+    AND (posts.canonical_post_id IS NULL OR $2::text <> '')
+    AND ($3::timestamp IS NULL OR posts.published_at >= $3::timestamp)
+    AND ($4::timestamp IS NULL OR posts.published_at < $4::timestamp)
+    AND ($5::text = '' OR posts.author ILIKE '%' || $5::text || '%')
+    ORDER BY rank DESC, posts.published_at DESC NULLS LAST
+    LIMIT $6
+`
+
+type SearchPostsParams struct {
+	Query       string
+	FeedUrl     string
+	Since       sql.NullTime
+	Until       sql.NullTime
+	Author      string
+	ResultLimit int32
+}
+
+type SearchPostsRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	PublishedAt sql.NullTime
+	Author      sql.NullString
+	FeedName    string
+	Rank        float32
+	Snippet     string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.FeedUrl,
+		arg.Since,
+		arg.Until,
+		arg.Author,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.Author,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostGUID = `-- name: SetPostGUID :exec
+UPDATE posts SET guid = $3
+WHERE feed_id = $1 AND url = $2 AND guid IS NULL
+`
+
+type SetPostGUIDParams struct {
+	FeedID uuid.UUID
+	Url    string
+	Guid   sql.NullString
+}
+
+// Adopts a post stored before guids were kept, so its next edit is recognized
+func (q *Queries) SetPostGUID(ctx context.Context, arg SetPostGUIDParams) error {
+	_, err := q.db.ExecContext(ctx, setPostGUID, arg.FeedID, arg.Url, arg.Guid)
+	return err
+}
+
+const updatePostContent = `-- name: UpdatePostContent :exec
+UPDATE posts SET title = $2, description = $3, content = $4, fingerprint = $5, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdatePostContentParams struct {
+	ID          uuid.UUID
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+	Fingerprint string
+}
+
+func (q *Queries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostContent,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Content,
+		arg.Fingerprint,
+	)
+	return err
+}
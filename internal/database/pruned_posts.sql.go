@@ -0,0 +1,50 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: pruned_posts.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const isPostPruned = `-- name: IsPostPruned :one
+SELECT EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE feed_id = $1
+    AND (url = $2 OR ($3::text <> '' AND guid = $3::text))
+)
+`
+
+type IsPostPrunedParams struct {
+	FeedID uuid.UUID
+	Url    string
+	Guid   string
+}
+
+func (q *Queries) IsPostPruned(ctx context.Context, arg IsPostPrunedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isPostPruned, arg.FeedID, arg.Url, arg.Guid)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const recordPrunedPosts = `-- name: RecordPrunedPosts :exec
+INSERT INTO pruned_posts (feed_id, url, guid, pruned_at)
+SELECT posts.feed_id, posts.url, posts.guid, NOW()
+    FROM posts
+    WHERE posts.id = ANY($1::uuid[])
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id)
+ON CONFLICT (feed_id, url) DO UPDATE SET guid = EXCLUDED.guid, pruned_at = EXCLUDED.pruned_at
+`
+
+// Starred posts and posts with duplicates are never deleted, so they aren't remembered either
+func (q *Queries) RecordPrunedPosts(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, recordPrunedPosts, pq.Array(ids))
+	return err
+}
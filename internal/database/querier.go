@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Querier interface {
+	ClaimFeedsToFetch(ctx context.Context, arg ClaimFeedsToFetchParams) ([]ClaimFeedsToFetchRow, error)
+	ClearFeedRetention(ctx context.Context, feedUrl string) (int64, error)
+	ClearFeedSchedule(ctx context.Context, feedUrl string) (int64, error)
+	CountLiveAggWorkers(ctx context.Context, lastSeenAt time.Time) (int64, error)
+	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
+	CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) ([]CreateFeedFollowRow, error)
+	CreateFetchLog(ctx context.Context, arg CreateFetchLogParams) error
+	CreatePost(ctx context.Context, arg CreatePostParams) (int64, error)
+	CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) error
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAggWorker(ctx context.Context, workerID string) error
+	DeleteFeedFollowRecord(ctx context.Context, arg DeleteFeedFollowRecordParams) error
+	DeletePosts(ctx context.Context, ids []uuid.UUID) (int64, error)
+	DeleteUser(ctx context.Context) error
+	FindCanonicalPost(ctx context.Context, arg FindCanonicalPostParams) (uuid.UUID, error)
+	GetFeedByURL(ctx context.Context, feedUrl string) (uuid.UUID, error)
+	GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error)
+	GetFeedNameById(ctx context.Context, id uuid.UUID) (string, error)
+	GetFeedSchedule(ctx context.Context, feedUrl string) (GetFeedScheduleRow, error)
+	GetFeedStats(ctx context.Context, arg GetFeedStatsParams) ([]GetFeedStatsRow, error)
+	GetFeeds(ctx context.Context) ([]Feed, error)
+	GetFeedsByStatus(ctx context.Context, status string) ([]Feed, error)
+	GetPost(ctx context.Context, id uuid.UUID) (GetPostRow, error)
+	GetPostByGUID(ctx context.Context, arg GetPostByGUIDParams) (GetPostByGUIDRow, error)
+	GetPostRevisions(ctx context.Context, postID uuid.UUID) ([]GetPostRevisionsRow, error)
+	GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error)
+	// Newest first, so a feed that was just switched on gets its recent posts before its backlog
+	GetPostsToExtract(ctx context.Context, limit int32) ([]GetPostsToExtractRow, error)
+	// A post is pruned when it falls outside the newest keep_last posts of its feed or is older than max_age_days,
+	// a limit of 0 is no limit. Starred posts are always kept, and so is a post that duplicates still point at,
+	// it holds the read state of the story.
+	GetPrunablePosts(ctx context.Context, arg GetPrunablePostsParams) ([]GetPrunablePostsRow, error)
+	// Newest first, the publish history agg estimates a feed's posting rate from
+	GetRecentPublishTimes(ctx context.Context, arg GetRecentPublishTimesParams) ([]sql.NullTime, error)
+	GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]GetStarredPostsRow, error)
+	GetUser(ctx context.Context, userName string) (User, error)
+	GetUserByName(ctx context.Context, userName string) (uuid.UUID, error)
+	GetUserNameById(ctx context.Context, id uuid.UUID) (string, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	IsPostPruned(ctx context.Context, arg IsPostPrunedParams) (bool, error)
+	// Only the worker holding the lease may reschedule the feed, a worker whose lease ran out
+	// and was taken over updates nothing
+	MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) (int64, error)
+	MarkPostRead(ctx context.Context, arg MarkPostReadParams) (int64, error)
+	MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) (int64, error)
+	MarkPostsReadBefore(ctx context.Context, arg MarkPostsReadBeforeParams) (int64, error)
+	// The post isn't tried again by agg, the article command can still fetch it
+	RecordArticleError(ctx context.Context, arg RecordArticleErrorParams) error
+	RecordFeedError(ctx context.Context, arg RecordFeedErrorParams) error
+	RecordFeedNotFound(ctx context.Context, id uuid.UUID) (int32, error)
+	RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error
+	// Starred posts and posts with duplicates are never deleted, so they aren't remembered either
+	RecordPrunedPosts(ctx context.Context, ids []uuid.UUID) error
+	ReleaseFeedLeases(ctx context.Context, leasedBy sql.NullString) error
+	RequestAllFeedsRefresh(ctx context.Context) (int64, error)
+	// Paused and dead feeds are never claimed, so they aren't queued either
+	RequestFeedRefresh(ctx context.Context, feedUrl string) (int64, error)
+	RequestFollowedFeedsRefresh(ctx context.Context, userID uuid.UUID) (int64, error)
+	ResetFeedNotFound(ctx context.Context, id uuid.UUID) error
+	ReviveFeed(ctx context.Context, feedUrl string) (Feed, error)
+	SaveArticle(ctx context.Context, arg SaveArticleParams) error
+	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error)
+	SetFeedExtractArticles(ctx context.Context, arg SetFeedExtractArticlesParams) (int64, error)
+	SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error
+	SetFeedNotBefore(ctx context.Context, arg SetFeedNotBeforeParams) error
+	// Only the limits that are given change, a NULL keeps what the feed has
+	SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) (int64, error)
+	// Only the settings that are given change, a NULL keeps what the feed has
+	SetFeedSchedule(ctx context.Context, arg SetFeedScheduleParams) (int64, error)
+	SetFeedStatus(ctx context.Context, arg SetFeedStatusParams) error
+	SetHostBackoff(ctx context.Context, arg SetHostBackoffParams) error
+	// Adopts a post stored before guids were kept, so its next edit is recognized
+	SetPostGUID(ctx context.Context, arg SetPostGUIDParams) error
+	StarPost(ctx context.Context, arg StarPostParams) (int64, error)
+	UnstarPost(ctx context.Context, arg UnstarPostParams) (int64, error)
+	UpdateFeedHints(ctx context.Context, arg UpdateFeedHintsParams) error
+	UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error
+	UpsertAggWorker(ctx context.Context, workerID string) error
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: feed_follow.sql
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeedFollow = `-- name: CreateFeedFollow :one
+
+INSERT INTO feed_follow(id, createdAt, updatedAt, user_id, feed_id)
+VALUES(?, ?, ?, ?, ?)
+RETURNING id, createdat, updatedat, user_id, feed_id
+`
+
+type CreateFeedFollowParams struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+// SQLite has no INSERT inside a WITH, so CreateFeedFollow is an insert followed by GetFeedFollow
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, createFeedFollow,
+		arg.ID,
+		arg.Createdat,
+		arg.Updatedat,
+		arg.UserID,
+		arg.FeedID,
+	)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.Createdat,
+		&i.Updatedat,
+		&i.UserID,
+		&i.FeedID,
+	)
+	return i, err
+}
+
+const deleteFeedFollowRecord = `-- name: DeleteFeedFollowRecord :exec
+DELETE FROM feed_follow
+WHERE feed_follow.user_id = ?1
+AND feed_follow.feed_id = (SELECT feeds.id FROM feeds WHERE feeds.feed_url = ?2)
+`
+
+type DeleteFeedFollowRecordParams struct {
+	UserID  uuid.UUID
+	FeedUrl string
+}
+
+func (q *Queries) DeleteFeedFollowRecord(ctx context.Context, arg DeleteFeedFollowRecordParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedFollowRecord, arg.UserID, arg.FeedUrl)
+	return err
+}
+
+const getFeedFollow = `-- name: GetFeedFollow :one
+SELECT
+    feed_follow.id, feed_follow.createdat, feed_follow.updatedat, feed_follow.user_id, feed_follow.feed_id,
+    feeds.feed_name AS feed_name,
+    users.user_name AS user_name
+    FROM feed_follow
+    INNER JOIN users ON feed_follow.user_id = users.id
+    INNER JOIN feeds ON feed_follow.feed_id = feeds.id
+    WHERE feed_follow.id = ?
+`
+
+type GetFeedFollowRow struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+	FeedName  string
+	UserName  string
+}
+
+func (q *Queries) GetFeedFollow(ctx context.Context, id uuid.UUID) (GetFeedFollowRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, id)
+	var i GetFeedFollowRow
+	err := row.Scan(
+		&i.ID,
+		&i.Createdat,
+		&i.Updatedat,
+		&i.UserID,
+		&i.FeedID,
+		&i.FeedName,
+		&i.UserName,
+	)
+	return i, err
+}
+
+const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
+SELECT
+    users.user_name, feeds.feed_name FROM feed_follow
+    INNER JOIN users ON feed_follow.user_id = users.id
+    INNER JOIN feeds ON feed_follow.feed_id = feeds.id
+    WHERE feed_follow.user_id = ?
+`
+
+type GetFeedFollowsForUserRow struct {
+	UserName string
+	FeedName string
+}
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i GetFeedFollowsForUserRow
+		if err := rows.Scan(&i.UserName, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
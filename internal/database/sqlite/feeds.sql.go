@@ -0,0 +1,659 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: feeds.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimFeedsToFetch = `-- name: ClaimFeedsToFetch :many
+UPDATE feeds SET leased_by = ?1, lease_expires_at = ?2
+WHERE id IN (
+    SELECT due.id
+    FROM feeds AS due
+    WHERE due.status = 'active'
+    AND (due.next_fetch_at IS NULL OR due.next_fetch_at <= CURRENT_TIMESTAMP OR due.refresh_requested_at IS NOT NULL)
+    AND (CAST(?3 AS BOOLEAN) = FALSE OR due.refresh_requested_at IS NOT NULL)
+    AND (due.not_before IS NULL OR due.not_before <= CURRENT_TIMESTAMP)
+    AND (due.lease_expires_at IS NULL OR due.lease_expires_at <= CURRENT_TIMESTAMP)
+    AND NOT EXISTS (
+        SELECT 1 FROM host_backoff
+        WHERE host_backoff.host = feed_host(due.feed_url)
+        AND host_backoff.not_before > CURRENT_TIMESTAMP
+    )
+    ORDER BY due.refresh_requested_at ASC NULLS LAST, due.next_fetch_at ASC NULLS FIRST, due.last_fetched_at ASC NULLS FIRST
+    LIMIT ?4
+)
+RETURNING id, feed_name, feed_url, last_fetched_at, fetch_interval_seconds,
+    ttl_minutes, skip_hours, skip_days, update_period, update_frequency,
+    cron_schedule, quiet_hours
+`
+
+type ClaimFeedsToFetchParams struct {
+	Worker         sql.NullString
+	LeaseExpiresAt sql.NullTime
+	OnlyRequested  bool
+	BatchSize      int64
+}
+
+type ClaimFeedsToFetchRow struct {
+	ID                   uuid.UUID
+	FeedName             string
+	FeedUrl              string
+	LastFetchedAt        sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	TtlMinutes           sql.NullInt32
+	SkipHours            string
+	SkipDays             string
+	UpdatePeriod         sql.NullString
+	UpdateFrequency      sql.NullInt32
+	CronSchedule         sql.NullString
+	QuietHours           sql.NullString
+}
+
+// SQLite has a single writer, so the UPDATE alone makes the claim atomic.
+// feed_host is registered by the store, see hostOf.
+func (q *Queries) ClaimFeedsToFetch(ctx context.Context, arg ClaimFeedsToFetchParams) ([]ClaimFeedsToFetchRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimFeedsToFetch,
+		arg.Worker,
+		arg.LeaseExpiresAt,
+		arg.OnlyRequested,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimFeedsToFetchRow
+	for rows.Next() {
+		var i ClaimFeedsToFetchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.LastFetchedAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			&i.SkipHours,
+			&i.SkipDays,
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const clearFeedRetention = `-- name: ClearFeedRetention :execrows
+UPDATE feeds SET retention_keep_last = NULL, retention_max_age_days = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?1
+`
+
+func (q *Queries) ClearFeedRetention(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearFeedRetention, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const clearFeedSchedule = `-- name: ClearFeedSchedule :execrows
+UPDATE feeds SET cron_schedule = NULL, quiet_hours = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?1
+`
+
+func (q *Queries) ClearFeedSchedule(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearFeedSchedule, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createFeed = `-- name: CreateFeed :one
+INSERT INTO feeds(id, createdAt, updatedAt, feed_name, feed_url, user_id)
+VALUES(?, ?, ?, ?, ?, ?)
+RETURNING id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles
+`
+
+type CreateFeedParams struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	FeedName  string
+	FeedUrl   string
+	UserID    uuid.UUID
+}
+
+func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, createFeed,
+		arg.ID,
+		arg.Createdat,
+		arg.Updatedat,
+		arg.FeedName,
+		arg.FeedUrl,
+		arg.UserID,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Createdat,
+		&i.Updatedat,
+		&i.FeedName,
+		&i.FeedUrl,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ConsecutiveNotFound,
+		&i.NotBefore,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.TtlMinutes,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.UpdatePeriod,
+		&i.UpdateFrequency,
+		&i.CronSchedule,
+		&i.QuietHours,
+		&i.LeasedBy,
+		&i.LeaseExpiresAt,
+		&i.RefreshRequestedAt,
+		&i.LastSuccessAt,
+		&i.LastNewItemAt,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeDays,
+		&i.ExtractArticles,
+	)
+	return i, err
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+SELECT id FROM feeds WHERE feed_url = ?
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, feedUrl string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, feedUrl)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getFeedNameById = `-- name: GetFeedNameById :one
+SELECT feed_name FROM feeds WHERE id = ?
+`
+
+func (q *Queries) GetFeedNameById(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getFeedNameById, id)
+	var feed_name string
+	err := row.Scan(&feed_name)
+	return feed_name, err
+}
+
+const getFeedSchedule = `-- name: GetFeedSchedule :one
+SELECT id, cron_schedule, quiet_hours FROM feeds
+WHERE feed_url = ?
+`
+
+type GetFeedScheduleRow struct {
+	ID           uuid.UUID
+	CronSchedule sql.NullString
+	QuietHours   sql.NullString
+}
+
+func (q *Queries) GetFeedSchedule(ctx context.Context, feedUrl string) (GetFeedScheduleRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedSchedule, feedUrl)
+	var i GetFeedScheduleRow
+	err := row.Scan(&i.ID, &i.CronSchedule, &i.QuietHours)
+	return i, err
+}
+
+const getFeeds = `-- name: GetFeeds :many
+SELECT id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles FROM feeds
+`
+
+func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Createdat,
+			&i.Updatedat,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ConsecutiveNotFound,
+			&i.NotBefore,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			&i.SkipHours,
+			&i.SkipDays,
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+			&i.LeasedBy,
+			&i.LeaseExpiresAt,
+			&i.RefreshRequestedAt,
+			&i.LastSuccessAt,
+			&i.LastNewItemAt,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeDays,
+			&i.ExtractArticles,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedsByStatus = `-- name: GetFeedsByStatus :many
+SELECT id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles FROM feeds WHERE status = ? ORDER BY updatedAt DESC
+`
+
+func (q *Queries) GetFeedsByStatus(ctx context.Context, status string) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Createdat,
+			&i.Updatedat,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.Status,
+			&i.StatusReason,
+			&i.ConsecutiveNotFound,
+			&i.NotBefore,
+			&i.NextFetchAt,
+			&i.FetchIntervalSeconds,
+			&i.TtlMinutes,
+			&i.SkipHours,
+			&i.SkipDays,
+			&i.UpdatePeriod,
+			&i.UpdateFrequency,
+			&i.CronSchedule,
+			&i.QuietHours,
+			&i.LeasedBy,
+			&i.LeaseExpiresAt,
+			&i.RefreshRequestedAt,
+			&i.LastSuccessAt,
+			&i.LastNewItemAt,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeDays,
+			&i.ExtractArticles,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFeedFetched = `-- name: MarkFeedFetched :execrows
+UPDATE feeds SET last_fetched_at = CURRENT_TIMESTAMP, next_fetch_at = ?1, fetch_interval_seconds = ?2,
+    leased_by = NULL, lease_expires_at = NULL, refresh_requested_at = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?3 AND leased_by = ?4
+`
+
+type MarkFeedFetchedParams struct {
+	NextFetchAt          sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	ID                   uuid.UUID
+	LeasedBy             sql.NullString
+}
+
+// Only the worker holding the lease may reschedule the feed, a worker whose lease ran out
+// and was taken over updates nothing
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markFeedFetched,
+		arg.NextFetchAt,
+		arg.FetchIntervalSeconds,
+		arg.ID,
+		arg.LeasedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordFeedError = `-- name: RecordFeedError :exec
+UPDATE feeds SET last_error = ?1, last_error_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type RecordFeedErrorParams struct {
+	LastError sql.NullString
+	ID        uuid.UUID
+}
+
+func (q *Queries) RecordFeedError(ctx context.Context, arg RecordFeedErrorParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedError, arg.LastError, arg.ID)
+	return err
+}
+
+const recordFeedNotFound = `-- name: RecordFeedNotFound :one
+UPDATE feeds SET consecutive_not_found = consecutive_not_found + 1, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING consecutive_not_found
+`
+
+func (q *Queries) RecordFeedNotFound(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedNotFound, id)
+	var consecutive_not_found int32
+	err := row.Scan(&consecutive_not_found)
+	return consecutive_not_found, err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :exec
+UPDATE feeds SET last_success_at = CURRENT_TIMESTAMP,
+    last_new_item_at = CASE WHEN CAST(?1 AS BOOLEAN) THEN CURRENT_TIMESTAMP ELSE last_new_item_at END
+WHERE id = ?2
+`
+
+type RecordFeedSuccessParams struct {
+	HadNewItems bool
+	ID          uuid.UUID
+}
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess, arg.HadNewItems, arg.ID)
+	return err
+}
+
+const releaseFeedLeases = `-- name: ReleaseFeedLeases :exec
+UPDATE feeds SET leased_by = NULL, lease_expires_at = NULL
+WHERE leased_by = ?
+`
+
+func (q *Queries) ReleaseFeedLeases(ctx context.Context, leasedBy sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedLeases, leasedBy)
+	return err
+}
+
+const requestAllFeedsRefresh = `-- name: RequestAllFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE status = 'active'
+`
+
+func (q *Queries) RequestAllFeedsRefresh(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestAllFeedsRefresh)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requestFeedRefresh = `-- name: RequestFeedRefresh :execrows
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE feed_url = ?
+AND status = 'active'
+`
+
+// Paused and dead feeds are never claimed, so they aren't queued either
+func (q *Queries) RequestFeedRefresh(ctx context.Context, feedUrl string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestFeedRefresh, feedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requestFollowedFeedsRefresh = `-- name: RequestFollowedFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE status = 'active'
+AND id IN (SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = ?)
+`
+
+func (q *Queries) RequestFollowedFeedsRefresh(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requestFollowedFeedsRefresh, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resetFeedNotFound = `-- name: ResetFeedNotFound :exec
+UPDATE feeds SET consecutive_not_found = 0
+WHERE id = ? AND consecutive_not_found > 0
+`
+
+func (q *Queries) ResetFeedNotFound(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resetFeedNotFound, id)
+	return err
+}
+
+const reviveFeed = `-- name: ReviveFeed :one
+UPDATE feeds SET status = 'active', status_reason = NULL, consecutive_not_found = 0, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?
+RETURNING id, createdat, updatedat, feed_name, feed_url, user_id, last_fetched_at, status, status_reason, consecutive_not_found, not_before, next_fetch_at, fetch_interval_seconds, ttl_minutes, skip_hours, skip_days, update_period, update_frequency, cron_schedule, quiet_hours, leased_by, lease_expires_at, refresh_requested_at, last_success_at, last_new_item_at, last_error, last_error_at, retention_keep_last, retention_max_age_days, extract_articles
+`
+
+func (q *Queries) ReviveFeed(ctx context.Context, feedUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, reviveFeed, feedUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Createdat,
+		&i.Updatedat,
+		&i.FeedName,
+		&i.FeedUrl,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.Status,
+		&i.StatusReason,
+		&i.ConsecutiveNotFound,
+		&i.NotBefore,
+		&i.NextFetchAt,
+		&i.FetchIntervalSeconds,
+		&i.TtlMinutes,
+		&i.SkipHours,
+		&i.SkipDays,
+		&i.UpdatePeriod,
+		&i.UpdateFrequency,
+		&i.CronSchedule,
+		&i.QuietHours,
+		&i.LeasedBy,
+		&i.LeaseExpiresAt,
+		&i.RefreshRequestedAt,
+		&i.LastSuccessAt,
+		&i.LastNewItemAt,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeDays,
+		&i.ExtractArticles,
+	)
+	return i, err
+}
+
+const setFeedExtractArticles = `-- name: SetFeedExtractArticles :execrows
+UPDATE feeds SET extract_articles = ?1, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?2
+`
+
+type SetFeedExtractArticlesParams struct {
+	ExtractArticles bool
+	FeedUrl         string
+}
+
+func (q *Queries) SetFeedExtractArticles(ctx context.Context, arg SetFeedExtractArticlesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedExtractArticles, arg.ExtractArticles, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedNextFetch = `-- name: SetFeedNextFetch :exec
+UPDATE feeds SET next_fetch_at = ?1, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type SetFeedNextFetchParams struct {
+	NextFetchAt sql.NullTime
+	ID          uuid.UUID
+}
+
+func (q *Queries) SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNextFetch, arg.NextFetchAt, arg.ID)
+	return err
+}
+
+const setFeedNotBefore = `-- name: SetFeedNotBefore :exec
+UPDATE feeds SET not_before = ?1, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type SetFeedNotBeforeParams struct {
+	NotBefore sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) SetFeedNotBefore(ctx context.Context, arg SetFeedNotBeforeParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNotBefore, arg.NotBefore, arg.ID)
+	return err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :execrows
+UPDATE feeds SET
+    retention_keep_last = COALESCE(?1, retention_keep_last),
+    retention_max_age_days = COALESCE(?2, retention_max_age_days),
+    updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?3
+`
+
+type SetFeedRetentionParams struct {
+	RetentionKeepLast   sql.NullInt32
+	RetentionMaxAgeDays sql.NullInt32
+	FeedUrl             string
+}
+
+// Only the limits that are given change, a NULL keeps what the feed has
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedRetention, arg.RetentionKeepLast, arg.RetentionMaxAgeDays, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedSchedule = `-- name: SetFeedSchedule :execrows
+UPDATE feeds SET
+    cron_schedule = COALESCE(?1, cron_schedule),
+    quiet_hours = COALESCE(?2, quiet_hours),
+    updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?3
+`
+
+type SetFeedScheduleParams struct {
+	CronSchedule sql.NullString
+	QuietHours   sql.NullString
+	FeedUrl      string
+}
+
+// Only the settings that are given change, a NULL keeps what the feed has
+func (q *Queries) SetFeedSchedule(ctx context.Context, arg SetFeedScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedSchedule, arg.CronSchedule, arg.QuietHours, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedStatus = `-- name: SetFeedStatus :exec
+UPDATE feeds SET status = ?1, status_reason = ?2, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?3
+`
+
+type SetFeedStatusParams struct {
+	Status       string
+	StatusReason sql.NullString
+	ID           uuid.UUID
+}
+
+func (q *Queries) SetFeedStatus(ctx context.Context, arg SetFeedStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedStatus, arg.Status, arg.StatusReason, arg.ID)
+	return err
+}
+
+const updateFeedHints = `-- name: UpdateFeedHints :exec
+UPDATE feeds SET ttl_minutes = ?1, skip_hours = ?2, skip_days = ?3,
+    update_period = ?4, update_frequency = ?5
+WHERE id = ?6
+`
+
+type UpdateFeedHintsParams struct {
+	TtlMinutes      sql.NullInt32
+	SkipHours       string
+	SkipDays        string
+	UpdatePeriod    sql.NullString
+	UpdateFrequency sql.NullInt32
+	ID              uuid.UUID
+}
+
+func (q *Queries) UpdateFeedHints(ctx context.Context, arg UpdateFeedHintsParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedHints,
+		arg.TtlMinutes,
+		arg.SkipHours,
+		arg.SkipDays,
+		arg.UpdatePeriod,
+		arg.UpdateFrequency,
+		arg.ID,
+	)
+	return err
+}
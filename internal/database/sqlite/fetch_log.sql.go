@@ -0,0 +1,117 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: fetch_log.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFetchLog = `-- name: CreateFetchLog :exec
+INSERT INTO fetch_log(id, feed_id, started_at, duration_ms, http_status, bytes, items_parsed, new_items, error)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateFetchLogParams struct {
+	ID          uuid.UUID
+	FeedID      uuid.UUID
+	StartedAt   time.Time
+	DurationMs  int32
+	HttpStatus  sql.NullInt32
+	Bytes       int64
+	ItemsParsed int32
+	NewItems    int32
+	Error       sql.NullString
+}
+
+func (q *Queries) CreateFetchLog(ctx context.Context, arg CreateFetchLogParams) error {
+	_, err := q.db.ExecContext(ctx, createFetchLog,
+		arg.ID,
+		arg.FeedID,
+		arg.StartedAt,
+		arg.DurationMs,
+		arg.HttpStatus,
+		arg.Bytes,
+		arg.ItemsParsed,
+		arg.NewItems,
+		arg.Error,
+	)
+	return err
+}
+
+const getFeedStats = `-- name: GetFeedStats :many
+WITH logs AS (
+    SELECT
+        fetch_log.id, fetch_log.feed_id, fetch_log.duration_ms, fetch_log.new_items, fetch_log.error,
+        row_number() OVER (PARTITION BY fetch_log.feed_id ORDER BY fetch_log.duration_ms) AS position,
+        COUNT(*) OVER (PARTITION BY fetch_log.feed_id) AS total
+    FROM fetch_log
+    WHERE fetch_log.started_at >= ?2
+)
+SELECT
+    feeds.id,
+    feeds.feed_name,
+    feeds.feed_url,
+    CAST(COUNT(logs.id) AS INTEGER) AS attempts,
+    CAST(COUNT(logs.id) FILTER (WHERE logs.error IS NULL) AS INTEGER) AS successes,
+    CAST(COALESCE(AVG(logs.duration_ms) FILTER (WHERE logs.position IN ((logs.total + 1) / 2, (logs.total + 2) / 2)), 0) AS REAL) AS median_duration_ms,
+    CAST(COALESCE(SUM(logs.new_items), 0) AS INTEGER) AS new_items
+    FROM feeds
+    LEFT JOIN logs ON logs.feed_id = feeds.id
+    WHERE CAST(?1 AS TEXT) = '' OR feeds.feed_url = CAST(?1 AS TEXT)
+    GROUP BY feeds.id, feeds.feed_name, feeds.feed_url
+    ORDER BY feeds.feed_name
+`
+
+type GetFeedStatsParams struct {
+	FeedUrl string
+	Since   time.Time
+}
+
+type GetFeedStatsRow struct {
+	ID               uuid.UUID
+	FeedName         string
+	FeedUrl          string
+	Attempts         int64
+	Successes        int64
+	MedianDurationMs float64
+	NewItems         int64
+}
+
+// SQLite has no percentile_cont, the median is the average of the middle one or two durations
+func (q *Queries) GetFeedStats(ctx context.Context, arg GetFeedStatsParams) ([]GetFeedStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedStats, arg.FeedUrl, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedStatsRow
+	for rows.Next() {
+		var i GetFeedStatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.Attempts,
+			&i.Successes,
+			&i.MedianDurationMs,
+			&i.NewItems,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
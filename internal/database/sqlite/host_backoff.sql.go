@@ -0,0 +1,29 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: host_backoff.sql
+
+package sqlite
+
+import (
+	"context"
+	"time"
+)
+
+const setHostBackoff = `-- name: SetHostBackoff :exec
+INSERT INTO host_backoff(host, not_before, updatedAt)
+VALUES(?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (host) DO UPDATE
+    SET not_before = MAX(host_backoff.not_before, excluded.not_before),
+        updatedAt = CURRENT_TIMESTAMP
+`
+
+type SetHostBackoffParams struct {
+	Host      string
+	NotBefore time.Time
+}
+
+func (q *Queries) SetHostBackoff(ctx context.Context, arg SetHostBackoffParams) error {
+	_, err := q.db.ExecContext(ctx, setHostBackoff, arg.Host, arg.NotBefore)
+	return err
+}
@@ -0,0 +1,141 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AggWorker struct {
+	WorkerID   string
+	StartedAt  time.Time
+	LastSeenAt time.Time
+}
+
+type Feed struct {
+	ID                   uuid.UUID
+	Createdat            time.Time
+	Updatedat            time.Time
+	FeedName             string
+	FeedUrl              string
+	UserID               uuid.UUID
+	LastFetchedAt        sql.NullTime
+	Status               string
+	StatusReason         sql.NullString
+	ConsecutiveNotFound  int32
+	NotBefore            sql.NullTime
+	NextFetchAt          sql.NullTime
+	FetchIntervalSeconds sql.NullInt32
+	TtlMinutes           sql.NullInt32
+	SkipHours            string
+	SkipDays             string
+	UpdatePeriod         sql.NullString
+	UpdateFrequency      sql.NullInt32
+	CronSchedule         sql.NullString
+	QuietHours           sql.NullString
+	LeasedBy             sql.NullString
+	LeaseExpiresAt       sql.NullTime
+	RefreshRequestedAt   sql.NullTime
+	LastSuccessAt        sql.NullTime
+	LastNewItemAt        sql.NullTime
+	LastError            sql.NullString
+	LastErrorAt          sql.NullTime
+	RetentionKeepLast    sql.NullInt32
+	RetentionMaxAgeDays  sql.NullInt32
+	ExtractArticles      bool
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID
+	Createdat time.Time
+	Updatedat time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+type FetchLog struct {
+	ID          uuid.UUID
+	FeedID      uuid.UUID
+	StartedAt   time.Time
+	DurationMs  int32
+	HttpStatus  sql.NullInt32
+	Bytes       int64
+	ItemsParsed int32
+	NewItems    int32
+	Error       sql.NullString
+}
+
+type HostBackoff struct {
+	Host      string
+	NotBefore time.Time
+	Updatedat time.Time
+}
+
+type Post struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Title            string
+	Url              string
+	Description      sql.NullString
+	PublishedAt      sql.NullTime
+	FeedID           uuid.UUID
+	CanonicalUrl     string
+	Fingerprint      string
+	CanonicalPostID  uuid.NullUUID
+	Content          sql.NullString
+	Author           sql.NullString
+	Guid             sql.NullString
+	ArticleHtml      sql.NullString
+	ArticleText      sql.NullString
+	ArticleFetchedAt sql.NullTime
+	ArticleError     sql.NullString
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostRevision struct {
+	ID          uuid.UUID
+	PostID      uuid.UUID
+	RevisedAt   time.Time
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
+}
+
+type PostsFt struct {
+	PostID      string
+	Title       string
+	Description string
+	Content     string
+	Article     string
+}
+
+type PrunedPost struct {
+	FeedID   uuid.UUID
+	Url      string
+	Guid     sql.NullString
+	PrunedAt time.Time
+}
+
+type User struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserName  string
+}
@@ -0,0 +1,81 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: post_reads.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const markPostRead = `-- name: MarkPostRead :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT ?1, COALESCE(posts.canonical_post_id, posts.id), CURRENT_TIMESTAMP
+    FROM posts WHERE posts.id = ?2
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkPostReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg MarkPostReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markPostUnread = `-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+    WHERE user_id = ?1
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = ?2)
+`
+
+type MarkPostUnreadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markPostsReadBefore = `-- name: MarkPostsReadBefore :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT DISTINCT ?1, COALESCE(posts.canonical_post_id, posts.id), CURRENT_TIMESTAMP
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE COALESCE(posts.published_at, posts.created_at) < ?2
+    AND (
+        (CAST(?3 AS TEXT) <> '' AND feeds.feed_url = CAST(?3 AS TEXT))
+        OR (CAST(?3 AS TEXT) = '' AND posts.feed_id IN (
+            SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = ?1
+        ))
+    )
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkPostsReadBeforeParams struct {
+	UserID  uuid.UUID
+	Before  sql.NullTime
+	FeedUrl string
+}
+
+func (q *Queries) MarkPostsReadBefore(ctx context.Context, arg MarkPostsReadBeforeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostsReadBefore, arg.UserID, arg.Before, arg.FeedUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: pruned_posts.sql
+
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const isPostPruned = `-- name: IsPostPruned :one
+SELECT CAST(EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE feed_id = ?1
+    AND (url = ?2 OR (CAST(?3 AS TEXT) <> '' AND guid = CAST(?3 AS TEXT)))
+) AS BOOLEAN)
+`
+
+type IsPostPrunedParams struct {
+	FeedID uuid.UUID
+	Url    string
+	Guid   string
+}
+
+func (q *Queries) IsPostPruned(ctx context.Context, arg IsPostPrunedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isPostPruned, arg.FeedID, arg.Url, arg.Guid)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const recordPrunedPosts = `-- name: RecordPrunedPosts :exec
+INSERT INTO pruned_posts (feed_id, url, guid, pruned_at)
+SELECT posts.feed_id, posts.url, posts.guid, CURRENT_TIMESTAMP
+    FROM posts
+    WHERE posts.id IN (/*SLICE:ids*/?)
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id)
+ON CONFLICT (feed_id, url) DO UPDATE SET guid = excluded.guid, pruned_at = excluded.pruned_at
+`
+
+// Starred posts and posts with duplicates are never deleted, so they aren't remembered either
+func (q *Queries) RecordPrunedPosts(ctx context.Context, ids []uuid.UUID) error {
+	query := recordPrunedPosts
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
@@ -0,0 +1,669 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/search"
+	"github.com/google/uuid"
+	msqlite "modernc.org/sqlite"
+)
+
+func init() {
+	// ClaimFeedsToFetch matches feeds against host_backoff by host, Postgres does this with a regexp
+	err := msqlite.RegisterDeterministicScalarFunction("feed_host", 1, func(ctx *msqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		feedURL, _ := args[0].(string)
+		return hostOf(feedURL), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func hostOf(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// Open opens the database file at path with foreign keys on. Times are written in a format
+// SQLite's date functions understand, and WAL lets agg and other commands share the file.
+func Open(path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error in opening the SQLite database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// utcDB stores every time in UTC, so the text timestamps SQLite compares sort in time order
+type utcDB struct {
+	db DBTX
+}
+
+func utcArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case sql.NullTime:
+			if v.Valid {
+				args[i] = sql.NullTime{Time: v.Time.UTC(), Valid: true}
+			}
+		}
+	}
+	return args
+}
+
+func (u utcDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return u.db.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (u utcDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return u.db.PrepareContext(ctx, query)
+}
+
+func (u utcDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return u.db.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (u utcDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return u.db.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+// Store runs the queries of the Postgres backend against SQLite. The SQLite queries in
+// sql/sqlite/queries generate their own types, Store converts them to the database package ones.
+type Store struct {
+	q *Queries
+}
+
+var _ database.Querier = (*Store)(nil)
+
+// NewStore wraps a database opened by Open, or a transaction on it
+func NewStore(db DBTX) *Store {
+	return &Store{q: New(utcDB{db: db})}
+}
+
+// Arrays are stored as JSON text, see sql/sqlite/schema
+func toJSON(v interface{}) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func skipHoursFromJSON(value string) []int32 {
+	hours := []int32{}
+	_ = json.Unmarshal([]byte(value), &hours)
+	return hours
+}
+
+func skipDaysFromJSON(value string) []string {
+	days := []string{}
+	_ = json.Unmarshal([]byte(value), &days)
+	return days
+}
+
+func toFeed(f Feed) database.Feed {
+	return database.Feed{
+		ID:                   f.ID,
+		Createdat:            f.Createdat,
+		Updatedat:            f.Updatedat,
+		FeedName:             f.FeedName,
+		FeedUrl:              f.FeedUrl,
+		UserID:               f.UserID,
+		LastFetchedAt:        f.LastFetchedAt,
+		Status:               f.Status,
+		StatusReason:         f.StatusReason,
+		ConsecutiveNotFound:  f.ConsecutiveNotFound,
+		NotBefore:            f.NotBefore,
+		NextFetchAt:          f.NextFetchAt,
+		FetchIntervalSeconds: f.FetchIntervalSeconds,
+		TtlMinutes:           f.TtlMinutes,
+		SkipHours:            skipHoursFromJSON(f.SkipHours),
+		SkipDays:             skipDaysFromJSON(f.SkipDays),
+		UpdatePeriod:         f.UpdatePeriod,
+		UpdateFrequency:      f.UpdateFrequency,
+		CronSchedule:         f.CronSchedule,
+		QuietHours:           f.QuietHours,
+		LeasedBy:             f.LeasedBy,
+		LeaseExpiresAt:       f.LeaseExpiresAt,
+		RefreshRequestedAt:   f.RefreshRequestedAt,
+		LastSuccessAt:        f.LastSuccessAt,
+		LastNewItemAt:        f.LastNewItemAt,
+		LastError:            f.LastError,
+		LastErrorAt:          f.LastErrorAt,
+		RetentionKeepLast:    f.RetentionKeepLast,
+		RetentionMaxAgeDays:  f.RetentionMaxAgeDays,
+		ExtractArticles:      f.ExtractArticles,
+	}
+}
+
+func toFeeds(feeds []Feed, err error) ([]database.Feed, error) {
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]database.Feed, len(feeds))
+	for i, f := range feeds {
+		converted[i] = toFeed(f)
+	}
+	return converted, nil
+}
+
+func toUsers(users []User, err error) ([]database.User, error) {
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]database.User, len(users))
+	for i, u := range users {
+		converted[i] = database.User(u)
+	}
+	return converted, nil
+}
+
+func (s *Store) ClaimFeedsToFetch(ctx context.Context, arg database.ClaimFeedsToFetchParams) ([]database.ClaimFeedsToFetchRow, error) {
+	rows, err := s.q.ClaimFeedsToFetch(ctx, ClaimFeedsToFetchParams{
+		Worker:         arg.Worker,
+		LeaseExpiresAt: arg.LeaseExpiresAt,
+		OnlyRequested:  arg.OnlyRequested,
+		BatchSize:      int64(arg.BatchSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]database.ClaimFeedsToFetchRow, len(rows))
+	for i, row := range rows {
+		claimed[i] = database.ClaimFeedsToFetchRow{
+			ID:                   row.ID,
+			FeedName:             row.FeedName,
+			FeedUrl:              row.FeedUrl,
+			LastFetchedAt:        row.LastFetchedAt,
+			FetchIntervalSeconds: row.FetchIntervalSeconds,
+			TtlMinutes:           row.TtlMinutes,
+			SkipHours:            skipHoursFromJSON(row.SkipHours),
+			SkipDays:             skipDaysFromJSON(row.SkipDays),
+			UpdatePeriod:         row.UpdatePeriod,
+			UpdateFrequency:      row.UpdateFrequency,
+			CronSchedule:         row.CronSchedule,
+			QuietHours:           row.QuietHours,
+		}
+	}
+	return claimed, nil
+}
+
+func (s *Store) ClearFeedRetention(ctx context.Context, feedUrl string) (int64, error) {
+	return s.q.ClearFeedRetention(ctx, feedUrl)
+}
+
+func (s *Store) ClearFeedSchedule(ctx context.Context, feedUrl string) (int64, error) {
+	return s.q.ClearFeedSchedule(ctx, feedUrl)
+}
+
+func (s *Store) CountLiveAggWorkers(ctx context.Context, lastSeenAt time.Time) (int64, error) {
+	return s.q.CountLiveAggWorkers(ctx, lastSeenAt)
+}
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	feed, err := s.q.CreateFeed(ctx, CreateFeedParams(arg))
+	if err != nil {
+		return database.Feed{}, err
+	}
+	return toFeed(feed), nil
+}
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) ([]database.CreateFeedFollowRow, error) {
+	follow, err := s.q.CreateFeedFollow(ctx, CreateFeedFollowParams(arg))
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.q.GetFeedFollow(ctx, follow.ID)
+	if err != nil {
+		return nil, err
+	}
+	return []database.CreateFeedFollowRow{database.CreateFeedFollowRow(row)}, nil
+}
+
+func (s *Store) CreateFetchLog(ctx context.Context, arg database.CreateFetchLogParams) error {
+	return s.q.CreateFetchLog(ctx, CreateFetchLogParams(arg))
+}
+
+func (s *Store) CreatePost(ctx context.Context, arg database.CreatePostParams) (int64, error) {
+	return s.q.CreatePost(ctx, CreatePostParams(arg))
+}
+
+func (s *Store) CreatePostRevision(ctx context.Context, arg database.CreatePostRevisionParams) error {
+	return s.q.CreatePostRevision(ctx, CreatePostRevisionParams(arg))
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	user, err := s.q.CreateUser(ctx, CreateUserParams(arg))
+	return database.User(user), err
+}
+
+func (s *Store) DeleteAggWorker(ctx context.Context, workerID string) error {
+	return s.q.DeleteAggWorker(ctx, workerID)
+}
+
+func (s *Store) DeleteFeedFollowRecord(ctx context.Context, arg database.DeleteFeedFollowRecordParams) error {
+	return s.q.DeleteFeedFollowRecord(ctx, DeleteFeedFollowRecordParams(arg))
+}
+
+func (s *Store) DeletePosts(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	return s.q.DeletePosts(ctx, ids)
+}
+
+func (s *Store) DeleteUser(ctx context.Context) error {
+	return s.q.DeleteUser(ctx)
+}
+
+func (s *Store) FindCanonicalPost(ctx context.Context, arg database.FindCanonicalPostParams) (uuid.UUID, error) {
+	return s.q.FindCanonicalPost(ctx, FindCanonicalPostParams(arg))
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, feedUrl string) (uuid.UUID, error) {
+	return s.q.GetFeedByURL(ctx, feedUrl)
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	rows, err := s.q.GetFeedFollowsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	follows := make([]database.GetFeedFollowsForUserRow, len(rows))
+	for i, row := range rows {
+		follows[i] = database.GetFeedFollowsForUserRow(row)
+	}
+	return follows, nil
+}
+
+func (s *Store) GetFeedNameById(ctx context.Context, id uuid.UUID) (string, error) {
+	return s.q.GetFeedNameById(ctx, id)
+}
+
+func (s *Store) GetFeedSchedule(ctx context.Context, feedUrl string) (database.GetFeedScheduleRow, error) {
+	row, err := s.q.GetFeedSchedule(ctx, feedUrl)
+	return database.GetFeedScheduleRow(row), err
+}
+
+func (s *Store) GetFeedStats(ctx context.Context, arg database.GetFeedStatsParams) ([]database.GetFeedStatsRow, error) {
+	rows, err := s.q.GetFeedStats(ctx, GetFeedStatsParams{FeedUrl: arg.FeedUrl, Since: arg.Since})
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]database.GetFeedStatsRow, len(rows))
+	for i, row := range rows {
+		stats[i] = database.GetFeedStatsRow{
+			ID:               row.ID,
+			FeedName:         row.FeedName,
+			FeedUrl:          row.FeedUrl,
+			Attempts:         int32(row.Attempts),
+			Successes:        int32(row.Successes),
+			MedianDurationMs: row.MedianDurationMs,
+			NewItems:         int32(row.NewItems),
+		}
+	}
+	return stats, nil
+}
+
+func (s *Store) GetFeeds(ctx context.Context) ([]database.Feed, error) {
+	return toFeeds(s.q.GetFeeds(ctx))
+}
+
+func (s *Store) GetFeedsByStatus(ctx context.Context, status string) ([]database.Feed, error) {
+	return toFeeds(s.q.GetFeedsByStatus(ctx, status))
+}
+
+func (s *Store) GetPost(ctx context.Context, id uuid.UUID) (database.GetPostRow, error) {
+	post, err := s.q.GetPost(ctx, id)
+	return database.GetPostRow(post), err
+}
+
+func (s *Store) GetPostByGUID(ctx context.Context, arg database.GetPostByGUIDParams) (database.GetPostByGUIDRow, error) {
+	post, err := s.q.GetPostByGUID(ctx, GetPostByGUIDParams(arg))
+	return database.GetPostByGUIDRow(post), err
+}
+
+func (s *Store) GetPostRevisions(ctx context.Context, postID uuid.UUID) ([]database.GetPostRevisionsRow, error) {
+	rows, err := s.q.GetPostRevisions(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]database.GetPostRevisionsRow, len(rows))
+	for i, row := range rows {
+		revisions[i] = database.GetPostRevisionsRow(row)
+	}
+	return revisions, nil
+}
+
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	rows, err := s.q.GetPostsForUser(ctx, GetPostsForUserParams{
+		UserID:      arg.UserID,
+		IncludeRead: arg.IncludeRead,
+		PostLimit:   int64(arg.PostLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.GetPostsForUserRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.GetPostsForUserRow{
+			ID:          row.ID,
+			Title:       row.Title,
+			Url:         row.Url,
+			Description: row.Description,
+			PublishedAt: row.PublishedAt,
+			FeedName:    row.FeedName,
+			AlsoIn:      row.AlsoIn,
+			IsRead:      row.IsRead != 0,
+		}
+	}
+	return posts, nil
+}
+
+func (s *Store) GetPostsToExtract(ctx context.Context, limit int32) ([]database.GetPostsToExtractRow, error) {
+	rows, err := s.q.GetPostsToExtract(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.GetPostsToExtractRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.GetPostsToExtractRow(row)
+	}
+	return posts, nil
+}
+
+func (s *Store) GetRecentPublishTimes(ctx context.Context, arg database.GetRecentPublishTimesParams) ([]sql.NullTime, error) {
+	return s.q.GetRecentPublishTimes(ctx, GetRecentPublishTimesParams{
+		FeedID:       arg.FeedID,
+		HistoryLimit: int64(arg.HistoryLimit),
+	})
+}
+
+func (s *Store) GetPrunablePosts(ctx context.Context, arg database.GetPrunablePostsParams) ([]database.GetPrunablePostsRow, error) {
+	rows, err := s.q.GetPrunablePosts(ctx, GetPrunablePostsParams{
+		DefaultKeepLast:   int64(arg.DefaultKeepLast),
+		DefaultMaxAgeDays: int64(arg.DefaultMaxAgeDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.GetPrunablePostsRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.GetPrunablePostsRow(row)
+	}
+	return posts, nil
+}
+
+func (s *Store) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsRow, error) {
+	rows, err := s.q.GetStarredPosts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.GetStarredPostsRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.GetStarredPostsRow(row)
+	}
+	return posts, nil
+}
+
+func (s *Store) GetUser(ctx context.Context, userName string) (database.User, error) {
+	user, err := s.q.GetUser(ctx, userName)
+	return database.User(user), err
+}
+
+func (s *Store) GetUserByName(ctx context.Context, userName string) (uuid.UUID, error) {
+	return s.q.GetUserByName(ctx, userName)
+}
+
+func (s *Store) GetUserNameById(ctx context.Context, id uuid.UUID) (string, error) {
+	return s.q.GetUserNameById(ctx, id)
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	return toUsers(s.q.GetUsers(ctx))
+}
+
+func (s *Store) IsPostPruned(ctx context.Context, arg database.IsPostPrunedParams) (bool, error) {
+	return s.q.IsPostPruned(ctx, IsPostPrunedParams(arg))
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) (int64, error) {
+	return s.q.MarkFeedFetched(ctx, MarkFeedFetchedParams{
+		NextFetchAt:          arg.NextFetchAt,
+		FetchIntervalSeconds: arg.FetchIntervalSeconds,
+		ID:                   arg.ID,
+		LeasedBy:             arg.LeasedBy,
+	})
+}
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) (int64, error) {
+	return s.q.MarkPostRead(ctx, MarkPostReadParams(arg))
+}
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) (int64, error) {
+	return s.q.MarkPostUnread(ctx, MarkPostUnreadParams(arg))
+}
+
+func (s *Store) MarkPostsReadBefore(ctx context.Context, arg database.MarkPostsReadBeforeParams) (int64, error) {
+	return s.q.MarkPostsReadBefore(ctx, MarkPostsReadBeforeParams{
+		UserID:  arg.UserID,
+		Before:  sql.NullTime{Time: arg.Before, Valid: true},
+		FeedUrl: arg.FeedUrl,
+	})
+}
+
+func (s *Store) RecordArticleError(ctx context.Context, arg database.RecordArticleErrorParams) error {
+	return s.q.RecordArticleError(ctx, RecordArticleErrorParams{
+		ArticleError: arg.ArticleError,
+		ID:           arg.ID,
+	})
+}
+
+func (s *Store) RecordPrunedPosts(ctx context.Context, ids []uuid.UUID) error {
+	return s.q.RecordPrunedPosts(ctx, ids)
+}
+
+func (s *Store) RecordFeedError(ctx context.Context, arg database.RecordFeedErrorParams) error {
+	return s.q.RecordFeedError(ctx, RecordFeedErrorParams{LastError: arg.LastError, ID: arg.ID})
+}
+
+func (s *Store) RecordFeedNotFound(ctx context.Context, id uuid.UUID) (int32, error) {
+	return s.q.RecordFeedNotFound(ctx, id)
+}
+
+func (s *Store) RecordFeedSuccess(ctx context.Context, arg database.RecordFeedSuccessParams) error {
+	return s.q.RecordFeedSuccess(ctx, RecordFeedSuccessParams(arg))
+}
+
+func (s *Store) ReleaseFeedLeases(ctx context.Context, leasedBy sql.NullString) error {
+	return s.q.ReleaseFeedLeases(ctx, leasedBy)
+}
+
+func (s *Store) RequestAllFeedsRefresh(ctx context.Context) (int64, error) {
+	return s.q.RequestAllFeedsRefresh(ctx)
+}
+
+func (s *Store) RequestFeedRefresh(ctx context.Context, feedUrl string) (int64, error) {
+	return s.q.RequestFeedRefresh(ctx, feedUrl)
+}
+
+func (s *Store) RequestFollowedFeedsRefresh(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.q.RequestFollowedFeedsRefresh(ctx, userID)
+}
+
+func (s *Store) ResetFeedNotFound(ctx context.Context, id uuid.UUID) error {
+	return s.q.ResetFeedNotFound(ctx, id)
+}
+
+func (s *Store) ReviveFeed(ctx context.Context, feedUrl string) (database.Feed, error) {
+	feed, err := s.q.ReviveFeed(ctx, feedUrl)
+	if err != nil {
+		return database.Feed{}, err
+	}
+	return toFeed(feed), nil
+}
+
+// searchPosts is written by hand because sqlc can't parse the FTS5 MATCH and auxiliary functions.
+// bm25 is lower for better matches, and its column weights follow the A/B/C/D weights of Postgres.
+const searchPosts = `
+SELECT
+    posts.id, posts.title, posts.url, posts.published_at, posts.author,
+    feeds.feed_name,
+    -bm25(posts_fts, 0.0, 1.0, 0.4, 0.2, 0.1) AS rank,
+    snippet(posts_fts, -1, '**', '**', '...', 30) AS snippet
+    FROM posts_fts
+    INNER JOIN posts ON posts.id = posts_fts.post_id
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts_fts MATCH ?1
+    AND (?2 = '' OR feeds.feed_url = ?2)
+    AND (posts.canonical_post_id IS NULL OR ?2 <> '')
+    AND (?3 IS NULL OR posts.published_at >= ?3)
+    AND (?4 IS NULL OR posts.published_at < ?4)
+    AND (?5 = '' OR posts.author LIKE '%' || ?5 || '%')
+    ORDER BY rank DESC, posts.published_at DESC NULLS LAST
+    LIMIT ?6
+`
+
+func (s *Store) SaveArticle(ctx context.Context, arg database.SaveArticleParams) error {
+	return s.q.SaveArticle(ctx, SaveArticleParams{
+		ArticleHtml: arg.ArticleHtml,
+		ArticleText: arg.ArticleText,
+		ID:          arg.ID,
+	})
+}
+
+func (s *Store) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	match, err := search.TSQueryToFTS5(arg.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.q.db.QueryContext(ctx, searchPosts, match, arg.FeedUrl, arg.Since, arg.Until, arg.Author, arg.ResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []database.SearchPostsRow
+	for rows.Next() {
+		var result database.SearchPostsRow
+		var rank float64
+		err := rows.Scan(&result.ID, &result.Title, &result.Url, &result.PublishedAt, &result.Author,
+			&result.FeedName, &rank, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		result.Rank = float32(rank)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (s *Store) SetFeedExtractArticles(ctx context.Context, arg database.SetFeedExtractArticlesParams) (int64, error) {
+	return s.q.SetFeedExtractArticles(ctx, SetFeedExtractArticlesParams{
+		ExtractArticles: arg.ExtractArticles,
+		FeedUrl:         arg.FeedUrl,
+	})
+}
+
+func (s *Store) SetFeedNextFetch(ctx context.Context, arg database.SetFeedNextFetchParams) error {
+	return s.q.SetFeedNextFetch(ctx, SetFeedNextFetchParams{
+		NextFetchAt: arg.NextFetchAt,
+		ID:          arg.ID,
+	})
+}
+
+func (s *Store) SetFeedNotBefore(ctx context.Context, arg database.SetFeedNotBeforeParams) error {
+	return s.q.SetFeedNotBefore(ctx, SetFeedNotBeforeParams{NotBefore: arg.NotBefore, ID: arg.ID})
+}
+
+func (s *Store) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) (int64, error) {
+	return s.q.SetFeedRetention(ctx, SetFeedRetentionParams{
+		RetentionKeepLast:   arg.RetentionKeepLast,
+		RetentionMaxAgeDays: arg.RetentionMaxAgeDays,
+		FeedUrl:             arg.FeedUrl,
+	})
+}
+
+func (s *Store) SetFeedSchedule(ctx context.Context, arg database.SetFeedScheduleParams) (int64, error) {
+	return s.q.SetFeedSchedule(ctx, SetFeedScheduleParams{
+		CronSchedule: arg.CronSchedule,
+		QuietHours:   arg.QuietHours,
+		FeedUrl:      arg.FeedUrl,
+	})
+}
+
+func (s *Store) SetFeedStatus(ctx context.Context, arg database.SetFeedStatusParams) error {
+	return s.q.SetFeedStatus(ctx, SetFeedStatusParams{
+		Status:       arg.Status,
+		StatusReason: arg.StatusReason,
+		ID:           arg.ID,
+	})
+}
+
+func (s *Store) SetHostBackoff(ctx context.Context, arg database.SetHostBackoffParams) error {
+	return s.q.SetHostBackoff(ctx, SetHostBackoffParams(arg))
+}
+
+func (s *Store) SetPostGUID(ctx context.Context, arg database.SetPostGUIDParams) error {
+	return s.q.SetPostGUID(ctx, SetPostGUIDParams{
+		Guid:   arg.Guid,
+		FeedID: arg.FeedID,
+		Url:    arg.Url,
+	})
+}
+
+func (s *Store) StarPost(ctx context.Context, arg database.StarPostParams) (int64, error) {
+	return s.q.StarPost(ctx, StarPostParams(arg))
+}
+
+func (s *Store) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	return s.q.UnstarPost(ctx, UnstarPostParams(arg))
+}
+
+func (s *Store) UpdateFeedHints(ctx context.Context, arg database.UpdateFeedHintsParams) error {
+	skipHours, err := toJSON(arg.SkipHours)
+	if err != nil {
+		return err
+	}
+	skipDays, err := toJSON(arg.SkipDays)
+	if err != nil {
+		return err
+	}
+
+	return s.q.UpdateFeedHints(ctx, UpdateFeedHintsParams{
+		TtlMinutes:      arg.TtlMinutes,
+		SkipHours:       skipHours,
+		SkipDays:        skipDays,
+		UpdatePeriod:    arg.UpdatePeriod,
+		UpdateFrequency: arg.UpdateFrequency,
+		ID:              arg.ID,
+	})
+}
+
+func (s *Store) UpdatePostContent(ctx context.Context, arg database.UpdatePostContentParams) error {
+	return s.q.UpdatePostContent(ctx, UpdatePostContentParams{
+		Title:       arg.Title,
+		Description: arg.Description,
+		Content:     arg.Content,
+		Fingerprint: arg.Fingerprint,
+		ID:          arg.ID,
+	})
+}
+
+func (s *Store) UpsertAggWorker(ctx context.Context, workerID string) error {
+	return s.q.UpsertAggWorker(ctx, workerID)
+}
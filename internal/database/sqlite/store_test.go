@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/search"
+	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
+)
+
+// newTestStore opens a new database file and applies every migration in sql/sqlite/schema
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, os.DirFS("../../../sql/sqlite/schema"))
+	if err != nil {
+		t.Fatalf("goose.NewProvider() error = %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("applying the migrations error = %v", err)
+	}
+	return NewStore(db)
+}
+
+func newFeed(t *testing.T, s *Store, userID uuid.UUID, feedURL string) database.Feed {
+	t.Helper()
+	feed, err := s.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID: uuid.New(), Createdat: time.Now(), Updatedat: time.Now(),
+		FeedName: feedURL, FeedUrl: feedURL, UserID: userID,
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	return feed
+}
+
+func newUser(t *testing.T, s *Store) database.User {
+	t.Helper()
+	user, err := s.CreateUser(context.Background(), database.CreateUserParams{
+		ID: uuid.New(), CreatedAt: time.Now(), UpdatedAt: time.Now(), UserName: "alice",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	return user
+}
+
+func TestStore_ClaimFeedsToFetch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	user := newUser(t, s)
+	recent := newFeed(t, s, user.ID, "https://recent.example/rss")
+	overdue := newFeed(t, s, user.ID, "https://overdue.example/rss")
+	future := newFeed(t, s, user.ID, "https://future.example/rss")
+
+	claim := func(worker string, batchSize int32) []database.ClaimFeedsToFetchRow {
+		rows, err := s.ClaimFeedsToFetch(ctx, database.ClaimFeedsToFetchParams{
+			Worker:         sql.NullString{String: worker, Valid: true},
+			LeaseExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+			BatchSize:      batchSize,
+		})
+		if err != nil {
+			t.Fatalf("ClaimFeedsToFetch() error = %v", err)
+		}
+		return rows
+	}
+
+	// only the lease holder can reschedule a feed
+	setup := sql.NullString{String: "setup", Valid: true}
+	nextFetch := map[uuid.UUID]time.Duration{recent.ID: -time.Minute, overdue.ID: -time.Hour, future.ID: time.Hour}
+	for id, offset := range nextFetch {
+		params := database.MarkFeedFetchedParams{ID: id, NextFetchAt: sql.NullTime{Time: time.Now().Add(offset), Valid: true}, LeasedBy: setup}
+		if marked, err := s.MarkFeedFetched(ctx, params); err != nil || marked != 0 {
+			t.Fatalf("MarkFeedFetched() without the lease = %d, %v, want nothing marked", marked, err)
+		}
+	}
+	claim(setup.String, 3)
+	for id, offset := range nextFetch {
+		params := database.MarkFeedFetchedParams{ID: id, NextFetchAt: sql.NullTime{Time: time.Now().Add(offset), Valid: true}, LeasedBy: setup}
+		if marked, err := s.MarkFeedFetched(ctx, params); err != nil || marked != 1 {
+			t.Fatalf("MarkFeedFetched() = %d, %v", marked, err)
+		}
+	}
+
+	// most overdue first, a leased feed isn't claimed again and a feed that isn't due is left alone
+	first, second, third := claim("one", 1), claim("two", 1), claim("three", 1)
+	if len(first) != 1 || first[0].ID != overdue.ID {
+		t.Errorf("first claim = %v, want the most overdue feed", first)
+	}
+	if len(second) != 1 || second[0].ID != recent.ID {
+		t.Errorf("second claim = %v, want the other due feed", second)
+	}
+	if len(third) != 0 {
+		t.Errorf("third claim = %v, want nothing due", third)
+	}
+
+	if err := s.ReleaseFeedLeases(ctx, sql.NullString{String: "one", Valid: true}); err != nil {
+		t.Fatalf("ReleaseFeedLeases() error = %v", err)
+	}
+	if again := claim("three", 1); len(again) != 1 || again[0].ID != overdue.ID {
+		t.Errorf("claim after release = %v, want the released feed", again)
+	}
+}
+
+func TestStore_postsDedupSearchAndPrune(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	user := newUser(t, s)
+	world := newFeed(t, s, user.ID, "https://world.example/rss")
+	markets := newFeed(t, s, user.ID, "https://markets.example/rss")
+
+	createPost := func(feedID uuid.UUID, title, postURL, description string, published time.Time, canonical uuid.NullUUID) (uuid.UUID, int64) {
+		t.Helper()
+		id := uuid.New()
+		created, err := s.CreatePost(ctx, database.CreatePostParams{
+			ID: id, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+			Title: title, Url: postURL, FeedID: feedID, CanonicalUrl: postURL, Fingerprint: title,
+			Description:     sql.NullString{String: description, Valid: true},
+			PublishedAt:     sql.NullTime{Time: published, Valid: true},
+			CanonicalPostID: canonical,
+			Guid:            sql.NullString{String: postURL, Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("CreatePost() error = %v", err)
+		}
+		return id, created
+	}
+
+	now := time.Now()
+	rates, _ := createPost(world.ID, "Rates rise", "https://world.example/rates", "The central bank raised interest rates again.", now, uuid.NullUUID{})
+	old, _ := createPost(world.ID, "Bridge reopens", "https://world.example/bridge", "The harbour bridge reopened.", now.AddDate(0, 0, -10), uuid.NullUUID{})
+	if _, created := createPost(world.ID, "Rates rise", "https://world.example/rates", "", now, uuid.NullUUID{}); created != 0 {
+		t.Errorf("CreatePost() of a stored url created %d posts, want 0", created)
+	}
+
+	// a story another feed already has is found by its url or fingerprint and stored as a duplicate
+	canonical, err := s.FindCanonicalPost(ctx, database.FindCanonicalPostParams{FeedID: markets.ID, CanonicalUrl: "https://markets.example/rates", Fingerprint: "Rates rise"})
+	if err != nil || canonical != rates {
+		t.Fatalf("FindCanonicalPost() = %v, %v, want the world post", canonical, err)
+	}
+	createPost(markets.ID, "Rates rise", "https://markets.example/rates", "The central bank raised interest rates again.", now, uuid.NullUUID{UUID: canonical, Valid: true})
+	if _, err := s.FindCanonicalPost(ctx, database.FindCanonicalPostParams{FeedID: world.ID, CanonicalUrl: "https://world.example/rates", Fingerprint: ""}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FindCanonicalPost() in the post's own feed error = %v, want sql.ErrNoRows", err)
+	}
+
+	query, err := search.ToTSQuery(`"interest rates" -crypto`)
+	if err != nil {
+		t.Fatalf("ToTSQuery() error = %v", err)
+	}
+	results, err := s.SearchPosts(ctx, database.SearchPostsParams{Query: query, ResultLimit: 10})
+	if err != nil {
+		t.Fatalf("SearchPosts() error = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != rates {
+		t.Errorf("SearchPosts() = %+v, want the world post once, without its duplicate", results)
+	}
+	query, _ = search.ToTSQuery("interest -rates")
+	if results, _ := s.SearchPosts(ctx, database.SearchPostsParams{Query: query, ResultLimit: 10}); len(results) != 0 {
+		t.Errorf("SearchPosts() with a negated word = %+v, want nothing", results)
+	}
+
+	prunable, err := s.GetPrunablePosts(ctx, database.GetPrunablePostsParams{DefaultMaxAgeDays: 7})
+	if err != nil {
+		t.Fatalf("GetPrunablePosts() error = %v", err)
+	}
+	if len(prunable) != 1 || prunable[0].ID != old {
+		t.Fatalf("GetPrunablePosts() = %+v, want the post older than a week", prunable)
+	}
+	if err := s.RecordPrunedPosts(ctx, []uuid.UUID{old}); err != nil {
+		t.Fatalf("RecordPrunedPosts() error = %v", err)
+	}
+	if deleted, err := s.DeletePosts(ctx, []uuid.UUID{old}); err != nil || deleted != 1 {
+		t.Fatalf("DeletePosts() = %d, %v, want 1", deleted, err)
+	}
+	pruned, err := s.IsPostPruned(ctx, database.IsPostPrunedParams{FeedID: world.ID, Url: "https://world.example/bridge", Guid: "https://world.example/bridge"})
+	if err != nil || !pruned {
+		t.Errorf("IsPostPruned() = %v, %v, want the deleted post remembered", pruned, err)
+	}
+	if pruned, _ := s.IsPostPruned(ctx, database.IsPostPrunedParams{FeedID: world.ID, Url: "https://world.example/rates", Guid: "https://world.example/rates"}); pruned {
+		t.Errorf("IsPostPruned() = true for a post that is still stored")
+	}
+}
@@ -0,0 +1,95 @@
+// Package dedup recognizes the same story arriving through different feeds,
+// by its canonicalized url or by a fingerprint of its content.
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Query parameters that only track where a click came from
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "mc_cid": true, "mc_eid": true,
+	"ref": true, "ref_src": true, "source": true, "cmpid": true, "_hsenc": true, "_hsmi": true,
+}
+
+// CanonicalURL normalizes a link so that the variants syndication partners produce compare equal:
+// no scheme, no "www.", lower case host, no default port, no fragment, no tracking parameters,
+// sorted query and no trailing slash
+func CanonicalURL(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(link)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(host)
+	b.WriteString(strings.TrimRight(u.EscapedPath(), "/"))
+	for i, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for j, value := range values {
+			if i == 0 && j == 0 {
+				b.WriteByte('?')
+			} else {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key) + "=" + url.QueryEscape(value))
+		}
+	}
+
+	return b.String()
+}
+
+var (
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeText strips markup and entities, lower cases and collapses whitespace
+func NormalizeText(text string) string {
+	text = tagPattern.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	text = strings.ToLower(text)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// Fingerprint hashes the normalized title and body of an item, the body being the description
+// or, without one, the content. It returns "" when the item has no body, so items that only share
+// a generic title like "Daily briefing" aren't taken for the same story and are matched by url alone.
+func Fingerprint(title, description, content string) string {
+	body := NormalizeText(description)
+	if body == "" {
+		body = NormalizeText(content)
+	}
+	if body == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(NormalizeText(title) + "\n" + body))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package dedup
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{"scheme and www", "http://www.example.com/story", "https://example.com/story"},
+		{"trailing slash", "https://example.com/story/", "https://example.com/story"},
+		{"tracking parameters", "https://example.com/story?utm_source=rss&utm_medium=feed&id=7", "https://example.com/story?id=7"},
+		{"fragment and case", "https://EXAMPLE.com/story#comments", "https://example.com/story"},
+		{"query order", "https://example.com/story?b=2&a=1", "https://example.com/story?a=1&b=2"},
+		{"default port", "https://example.com:443/story", "https://example.com/story"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := CanonicalURL(tt.a), CanonicalURL(tt.b); got != want {
+				t.Errorf("CanonicalURL(%q) = %q, CanonicalURL(%q) = %q, want equal", tt.a, got, tt.b, want)
+			}
+		})
+	}
+
+	if CanonicalURL("https://example.com/a") == CanonicalURL("https://example.com/b") {
+		t.Errorf("different paths should not be equal")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("Big News", "<p>Something   happened.</p>", "")
+	b := Fingerprint("big news", "Something happened.", "")
+	if a != b {
+		t.Errorf("Fingerprint() differs for the same text with different markup: %q != %q", a, b)
+	}
+	if Fingerprint("Big News", "Something else happened.", "") == a {
+		t.Errorf("Fingerprint() should differ for different descriptions")
+	}
+	if Fingerprint("", "<br/>", "") != "" {
+		t.Errorf("Fingerprint() of an empty item should be empty")
+	}
+	if Fingerprint("Daily briefing", "", "") != "" {
+		t.Errorf("Fingerprint() of an item with only a title should be empty")
+	}
+	if Fingerprint("Big News", "", "<p>Something happened.</p>") != a {
+		t.Errorf("Fingerprint() should fall back to the content when there is no description")
+	}
+}
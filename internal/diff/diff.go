@@ -0,0 +1,166 @@
+// Package diff compares two versions of a text word by word, for showing how a post was edited.
+package diff
+
+import "strings"
+
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Chunk is a run of words that both versions share, or that only one of them has
+type Chunk struct {
+	Op   Op
+	Text string
+}
+
+// Texts longer than this, after the shared start and end are taken off, are compared as a whole
+// instead of word by word, which keeps the comparison table below a few million cells
+const maxTable = 1 << 22
+
+// Words diffs two texts word by word. Both are split on whitespace, so reflowed text compares
+// equal. The words they start and end with are taken off first, since edits are usually small
+// and local, and the longest common subsequence of what is left decides which words were
+// deleted or inserted. Where a run of words was replaced, deletions come before insertions.
+func Words(old, new string) []Chunk {
+	a, b := strings.Fields(old), strings.Fields(new)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var chunks []Chunk
+	add := func(op Op, words []string) {
+		if len(words) == 0 {
+			return
+		}
+		if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+			chunks[n-1].Text += " " + strings.Join(words, " ")
+			return
+		}
+		chunks = append(chunks, Chunk{Op: op, Text: strings.Join(words, " ")})
+	}
+
+	add(Equal, a[:prefix])
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(middleA)*len(middleB) > maxTable {
+		add(Delete, middleA)
+		add(Insert, middleB)
+	} else {
+		for _, step := range lcsSteps(middleA, middleB) {
+			add(step.op, []string{step.word})
+		}
+	}
+	add(Equal, a[len(a)-suffix:])
+
+	return chunks
+}
+
+type step struct {
+	op   Op
+	word string
+}
+
+func lcsSteps(a, b []string) []step {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var steps []step
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			steps = append(steps, step{Equal, a[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			steps = append(steps, step{Delete, a[i]})
+			i++
+		default:
+			steps = append(steps, step{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		steps = append(steps, step{Delete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		steps = append(steps, step{Insert, b[j]})
+	}
+	return steps
+}
+
+// Changed reports whether the chunks contain any deletion or insertion
+func Changed(chunks []Chunk) bool {
+	for _, chunk := range chunks {
+		if chunk.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders chunks like git diff --word-diff, [-deleted-] and {+inserted+}.
+// Shared runs keep only context words next to a change, a negative context keeps everything.
+func Format(chunks []Chunk, context int) string {
+	var parts []string
+	for i, chunk := range chunks {
+		switch chunk.Op {
+		case Delete:
+			parts = append(parts, "[-"+chunk.Text+"-]")
+		case Insert:
+			parts = append(parts, "{+"+chunk.Text+"+}")
+		default:
+			parts = append(parts, trimContext(chunk.Text, context, i > 0, i < len(chunks)-1))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// trimContext shortens a shared run to the words next to the changes before and after it
+func trimContext(text string, context int, changeBefore, changeAfter bool) string {
+	words := strings.Fields(text)
+	if context < 0 {
+		return text
+	}
+
+	switch {
+	case changeBefore && changeAfter:
+		if len(words) <= 2*context+1 {
+			return text
+		}
+		return strings.Join(words[:context], " ") + " ... " + strings.Join(words[len(words)-context:], " ")
+	case changeAfter:
+		if len(words) <= context {
+			return text
+		}
+		return "... " + strings.Join(words[len(words)-context:], " ")
+	case changeBefore:
+		if len(words) <= context {
+			return text
+		}
+		return strings.Join(words[:context], " ") + " ..."
+	default:
+		return text
+	}
+}
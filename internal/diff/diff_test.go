@@ -0,0 +1,58 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []Chunk
+	}{
+		{"same", "a b c", "a  b\nc", []Chunk{{Equal, "a b c"}}},
+		{"replaced word", "the mayor said", "the governor said", []Chunk{{Equal, "the"}, {Delete, "mayor"}, {Insert, "governor"}, {Equal, "said"}}},
+		{"inserted words", "prices rose", "prices rose sharply today", []Chunk{{Equal, "prices rose"}, {Insert, "sharply today"}}},
+		{"deleted word", "an alleged fraud", "a fraud", []Chunk{{Delete, "an alleged"}, {Insert, "a"}, {Equal, "fraud"}}},
+		{"moved word", "a b c d", "a c b d", []Chunk{{Equal, "a"}, {Delete, "b"}, {Equal, "c"}, {Insert, "b"}, {Equal, "d"}}},
+		{"empty old", "", "new text", []Chunk{{Insert, "new text"}}},
+		{"both empty", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Words(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Words() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	chunks := Words(
+		"one two three four five six seven eight nine ten",
+		"one two three four FIVE six seven eight nine ten",
+	)
+	if !Changed(chunks) {
+		t.Fatalf("Changed() = false, want true")
+	}
+
+	tests := []struct {
+		name    string
+		context int
+		want    string
+	}{
+		{"everything", -1, "one two three four [-five-] {+FIVE+} six seven eight nine ten"},
+		{"two words of context", 2, "... three four [-five-] {+FIVE+} six seven ..."},
+		{"context covers the text", 10, "one two three four [-five-] {+FIVE+} six seven eight nine ten"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(chunks, tt.context); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
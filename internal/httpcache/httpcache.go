@@ -0,0 +1,141 @@
+// Package httpcache records raw feed responses to disk and replays them later without touching the network,
+// so a response that broke the parser can be reproduced after the publisher has changed it.
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const responseExt = ".http"
+
+// Recorder is an http.RoundTripper that saves every response it passes through
+// (status line, headers and body) under Dir, one file per fetch
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error in reading the response body to record it: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	dump, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		return nil, fmt.Errorf("error in dumping the response to record it: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	dir := urlDir(r.Dir, req.URL.String())
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error in creating the cache directory %s: %w", dir, err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "url.txt"), []byte(req.URL.String()+"\n"), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error in writing the url of the recorded response: %w", err)
+	}
+
+	fileName := time.Now().UTC().Format("20060102T150405.000000000") + responseExt
+	err = os.WriteFile(filepath.Join(dir, fileName), dump, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error in writing the recorded response: %w", err)
+	}
+
+	return res, nil
+}
+
+// Replayer is an http.RoundTripper that answers every request with the latest response
+// recorded for its url under Dir. It never makes a network request.
+type Replayer struct {
+	Dir string
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, err := Latest(r.Dir, req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadResponse(path, req)
+}
+
+// Latest returns the path of the most recent recording for url
+func Latest(dir, url string) (string, error) {
+	recordings, err := Recordings(dir, url)
+	if err != nil {
+		return "", err
+	}
+	if len(recordings) == 0 {
+		return "", fmt.Errorf("no recorded response for %s in %s", url, dir)
+	}
+
+	return recordings[len(recordings)-1], nil
+}
+
+// Recordings lists the recorded responses for url, oldest first
+func Recordings(dir, url string) ([]string, error) {
+	entries, err := os.ReadDir(urlDir(dir, url))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error in reading the cache directory: %w", err)
+	}
+
+	var recordings []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), responseExt) {
+			recordings = append(recordings, filepath.Join(urlDir(dir, url), entry.Name()))
+		}
+	}
+	sort.Strings(recordings)
+
+	return recordings, nil
+}
+
+// ReadResponse parses a recorded response file back into an *http.Response for req
+func ReadResponse(path string, req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading the recorded response %s: %w", path, err)
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing the recorded response %s: %w", path, err)
+	}
+
+	return res, nil
+}
+
+func urlDir(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])[:16])
+}
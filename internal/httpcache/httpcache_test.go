@@ -0,0 +1,49 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Header().Set("Retry-After", "120")
+		io.WriteString(w, "<rss><channel><title>recorded</title></channel></rss>")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	recorder := &http.Client{Transport: &Recorder{Dir: dir}}
+	res, err := recorder.Get(server.URL + "/feed.xml")
+	if err != nil {
+		t.Fatalf("recording request failed: %v", err)
+	}
+	io.ReadAll(res.Body)
+	res.Body.Close()
+
+	server.Close()
+
+	replayer := &http.Client{Transport: &Replayer{Dir: dir}}
+	res, err = replayer.Get(server.URL + "/feed.xml")
+	if err != nil {
+		t.Fatalf("replaying request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	if got, want := string(body), "<rss><channel><title>recorded</title></channel></rss>"; got != want {
+		t.Errorf("replayed body = %q, want %q", got, want)
+	}
+	if got := res.Header.Get("Retry-After"); got != "120" {
+		t.Errorf("replayed Retry-After = %q, want %q", got, "120")
+	}
+
+	_, err = replayer.Get(server.URL + "/missing.xml")
+	if err == nil {
+		t.Errorf("replaying an unrecorded url should fail")
+	}
+}
@@ -0,0 +1,551 @@
+// Package readability finds the main text of an article page and drops what surrounds it,
+// navigation, ads, share buttons, comments and related links, in the spirit of Mozilla's Readability.
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoContent is returned for pages where nothing looks like article text
+var ErrNoContent = errors.New("no article content found on the page")
+
+// Article is the main content of a page
+type Article struct {
+	Title string
+	// HTML keeps the structure of the content, paragraphs, headings, lists, links and images,
+	// with the page's classes, styles and scripts taken out and every link made absolute
+	HTML string
+	// Text is the same content as plain text, with a blank line between paragraphs
+	Text string
+}
+
+// Containers whose text is no part of an article, they are dropped with everything in them
+var dropTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Menu: true,
+	atom.Form: true, atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true,
+	atom.Iframe: true, atom.Object: true, atom.Embed: true, atom.Canvas: true, atom.Svg: true,
+	atom.Link: true, atom.Meta: true, atom.Dialog: true,
+}
+
+var (
+	// unlikelyPattern matches the class or id of page furniture, unless maybePattern matches it too
+	unlikelyPattern = regexp.MustCompile(`(?i)-ad-|^ads?$|^ad-|advert|banner|breadcrumb|combx|comment|community|cookie|consent|disqus|gdpr|masthead|modal|newsletter|outbrain|pager|pagination|popup|promo|related|remark|replies|share|sharing|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|taboola|widget`)
+	maybePattern    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+
+	positivePattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativePattern = regexp.MustCompile(`(?i)-ad-|hidden|banner|combx|comment|com-|contact|foot|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+
+	hiddenStylePattern = regexp.MustCompile(`(?i)display\s*:\s*none|visibility\s*:\s*hidden`)
+)
+
+// Roles that mark landmarks around the article rather than the article itself
+var dropRoles = map[string]bool{
+	"navigation": true, "banner": true, "complementary": true, "contentinfo": true,
+	"dialog": true, "alertdialog": true, "menu": true, "menubar": true, "search": true,
+}
+
+// Elements whose own text is scored, divs count only when they hold no other blocks
+var scoredTags = map[atom.Atom]bool{
+	atom.P: true, atom.Pre: true, atom.Td: true, atom.Blockquote: true,
+}
+
+// Elements that start a new block of text
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Blockquote: true, atom.Dd: true, atom.Div: true,
+	atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Hr: true,
+	atom.Li: true, atom.Main: true, atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true,
+	atom.Table: true, atom.Tbody: true, atom.Td: true, atom.Tfoot: true, atom.Th: true,
+	atom.Thead: true, atom.Tr: true, atom.Ul: true, atom.Caption: true,
+}
+
+// Elements kept in Article.HTML with the attributes they keep, anything else is unwrapped
+var keptTags = map[atom.Atom][]string{
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Div: nil, atom.Section: nil,
+	atom.H1: nil, atom.H2: nil, atom.H3: nil, atom.H4: nil, atom.H5: nil, atom.H6: nil,
+	atom.A: {"href", "title"}, atom.Img: {"src", "alt", "title"},
+	atom.Figure: nil, atom.Figcaption: nil, atom.Blockquote: nil, atom.Pre: nil, atom.Code: nil,
+	atom.Em: nil, atom.Strong: nil, atom.B: nil, atom.I: nil, atom.U: nil, atom.S: nil,
+	atom.Sub: nil, atom.Sup: nil, atom.Mark: nil, atom.Small: nil, atom.Abbr: {"title"},
+	atom.Ul: nil, atom.Ol: nil, atom.Li: nil, atom.Dl: nil, atom.Dt: nil, atom.Dd: nil,
+	atom.Table: nil, atom.Caption: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tfoot: nil,
+	atom.Tr: nil, atom.Th: {"colspan", "rowspan"}, atom.Td: {"colspan", "rowspan"},
+}
+
+// Extract finds the article in an HTML page. The title comes from og:title, falling back to
+// <title> and then the first <h1>. Scripts, styles, navigation, forms, hidden elements and
+// anything whose class or id looks like ads, comments or sharing are dropped, then every
+// paragraph is scored by its length and commas, and the score is added to its parent and, less
+// of it, to the ancestors above. The best scored container, scaled down by how much of its text
+// is links, is the article; siblings that score close to it or read like prose are kept too,
+// since articles are often split across containers. The result is cleaned down to plain markup
+// with absolute links, and its text is taken from the same tree.
+func Extract(r io.Reader, pageURL *url.URL) (Article, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Article{}, err
+	}
+
+	article := Article{Title: findTitle(doc)}
+
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	removeUnlikely(body)
+
+	scores := map[*html.Node]float64{}
+	candidates := scoreParagraphs(body, scores)
+
+	var top *html.Node
+	topScore := 0.0
+	for _, node := range candidates {
+		score := scores[node] * (1 - linkDensity(node))
+		scores[node] = score
+		if top == nil || score > topScore {
+			top, topScore = node, score
+		}
+	}
+	if top == nil {
+		top = body
+	}
+
+	var content []*html.Node
+	for _, node := range withSiblings(top, topScore, scores) {
+		content = append(content, clean(node, pageURL)...)
+	}
+
+	var markup bytes.Buffer
+	var text textWriter
+	for _, node := range content {
+		if err := html.Render(&markup, node); err != nil {
+			return Article{}, err
+		}
+		text.write(node)
+	}
+
+	article.HTML = markup.String()
+	article.Text = text.String()
+	if article.Text == "" {
+		return article, ErrNoContent
+	}
+	return article, nil
+}
+
+func findTitle(doc *html.Node) string {
+	var title string
+	walk(doc, func(n *html.Node) bool {
+		if n.DataAtom == atom.Meta && (attr(n, "property") == "og:title" || attr(n, "name") == "twitter:title") {
+			title = strings.TrimSpace(attr(n, "content"))
+		}
+		return title == ""
+	})
+	if title != "" {
+		return title
+	}
+
+	if node := findFirst(doc, atom.Title); node != nil {
+		if title = innerText(node); title != "" {
+			return title
+		}
+	}
+	if node := findFirst(doc, atom.H1); node != nil {
+		return innerText(node)
+	}
+	return ""
+}
+
+// removeUnlikely takes out the elements that are never article text
+func removeUnlikely(root *html.Node) {
+	var unlikely []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.CommentNode {
+			unlikely = append(unlikely, n)
+			return false
+		}
+		if n.Type != html.ElementNode || n == root {
+			return true
+		}
+		if isUnlikely(n) {
+			unlikely = append(unlikely, n)
+			return false
+		}
+		return true
+	})
+
+	for _, n := range unlikely {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	if dropTags[n.DataAtom] || dropRoles[attr(n, "role")] {
+		return true
+	}
+	if hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" || hiddenStylePattern.MatchString(attr(n, "style")) {
+		return true
+	}
+	// The container named after the article is kept even when its class says otherwise
+	if n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyPattern.MatchString(match) && !maybePattern.MatchString(match)
+}
+
+// scoreParagraphs adds the score of every paragraph to its ancestors and returns them in the order they were first scored
+func scoreParagraphs(root *html.Node, scores map[*html.Node]float64) []*html.Node {
+	var candidates []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if !scoredTags[n.DataAtom] && !(n.DataAtom == atom.Div && !hasBlockChild(n)) {
+			return true
+		}
+
+		text := innerText(n)
+		if len(text) < 25 {
+			return true
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+
+		// The parent gets the full score, the grandparent half and the one above a sixth
+		divider := []float64{1, 2, 6}
+		ancestor := n.Parent
+		for level := 0; level < len(divider) && ancestor != nil && ancestor.Type == html.ElementNode; level++ {
+			if _, ok := scores[ancestor]; !ok {
+				scores[ancestor] = initialScore(ancestor)
+				candidates = append(candidates, ancestor)
+			}
+			scores[ancestor] += score / divider[level]
+			ancestor = ancestor.Parent
+		}
+		return false
+	})
+	return candidates
+}
+
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.DataAtom {
+	case atom.Article:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	return score
+}
+
+// classWeight is positive for classes and ids that name the content and negative for the rest of the page
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativePattern.MatchString(name) {
+			weight -= 25
+		}
+		if positivePattern.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// withSiblings returns top along with the siblings next to it that belong to the article
+func withSiblings(top *html.Node, topScore float64, scores map[*html.Node]float64) []*html.Node {
+	if top.Parent == nil || top.DataAtom == atom.Body {
+		return []*html.Node{top}
+	}
+
+	threshold := max(10, topScore*0.2)
+	var nodes []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		bonus := 0.0
+		if class := attr(sibling, "class"); class != "" && class == attr(top, "class") {
+			bonus = topScore * 0.2
+		}
+		if score, ok := scores[sibling]; ok && score+bonus >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		if sibling.DataAtom == atom.P {
+			text := innerText(sibling)
+			density := linkDensity(sibling)
+			if len(text) > 80 && density < 0.25 {
+				nodes = append(nodes, sibling)
+			} else if len(text) > 0 && density == 0 && (strings.Contains(text, ". ") || strings.HasSuffix(text, ".")) {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+	return nodes
+}
+
+// clean copies n down to the kept tags and attributes, an unwrapped element is replaced by its children
+func clean(n *html.Node, pageURL *url.URL) []*html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: n.Data}}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	if isJunk(n) {
+		return nil
+	}
+
+	var children []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, clean(child, pageURL)...)
+	}
+
+	attrs, kept := keptTags[n.DataAtom]
+	if !kept {
+		return children
+	}
+
+	copied := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom}
+	for _, name := range attrs {
+		value := attr(n, name)
+		switch name {
+		case "href":
+			value = resolve(pageURL, value)
+		case "src":
+			// Lazy loaded images keep the real address in data-src
+			if lazy := attr(n, "data-src"); lazy != "" {
+				value = lazy
+			}
+			value = resolve(pageURL, value)
+		}
+		if value != "" {
+			copied.Attr = append(copied.Attr, html.Attribute{Key: name, Val: value})
+		}
+	}
+
+	switch n.DataAtom {
+	case atom.Img:
+		if attr(copied, "src") == "" {
+			return nil
+		}
+	case atom.A:
+		if attr(copied, "href") == "" {
+			return children
+		}
+	}
+
+	for _, child := range children {
+		copied.AppendChild(child)
+	}
+	return []*html.Node{copied}
+}
+
+// isJunk is true for what is left of the page furniture inside the article: lists of links,
+// containers named like ads or comments, and blocks without text or images
+func isJunk(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Br, atom.Hr, atom.Img:
+		return false
+	case atom.A, atom.Em, atom.Strong, atom.B, atom.I, atom.Code, atom.Span, atom.Tr, atom.Td, atom.Th:
+		return false
+	}
+
+	text := innerText(n)
+	if text == "" && findFirst(n, atom.Img) == nil {
+		return true
+	}
+
+	switch n.DataAtom {
+	case atom.Div, atom.Section, atom.Ul, atom.Ol, atom.Table:
+		if classWeight(n) < 0 {
+			return true
+		}
+		if linkDensity(n) > 0.5 && len(text) < 500 {
+			return true
+		}
+	}
+	return false
+}
+
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(strings.ToLower(ref), "javascript:") {
+		return ""
+	}
+	if base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// textWriter collects the text of the cleaned tree, one block of text per paragraph
+type textWriter struct {
+	out       strings.Builder
+	paragraph strings.Builder
+}
+
+func (w *textWriter) write(n *html.Node) {
+	switch {
+	case n.Type == html.TextNode:
+		w.paragraph.WriteString(n.Data)
+		return
+	case n.DataAtom == atom.Br:
+		w.flush("\n")
+		return
+	case n.DataAtom == atom.Pre:
+		w.flush("\n\n")
+		w.appendText(strings.Trim(innerRawText(n), "\n"), "\n\n")
+		return
+	}
+
+	block := blockTags[n.DataAtom]
+	if block {
+		w.flush("\n\n")
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		w.write(child)
+	}
+	if block {
+		w.flush("\n\n")
+	}
+}
+
+// flush ends the paragraph, separator goes between it and the text before
+func (w *textWriter) flush(separator string) {
+	text := strings.Join(strings.Fields(w.paragraph.String()), " ")
+	w.paragraph.Reset()
+	w.appendText(text, separator)
+}
+
+func (w *textWriter) appendText(text, separator string) {
+	if text == "" {
+		return
+	}
+	if w.out.Len() > 0 {
+		w.out.WriteString(separator)
+	}
+	w.out.WriteString(text)
+}
+
+func (w *textWriter) String() string {
+	w.flush("\n\n")
+	return w.out.String()
+}
+
+// walk visits n and everything below it, visit returns false to skip the children of a node
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		walk(child, visit)
+		child = next
+	}
+}
+
+func findFirst(root *html.Node, tag atom.Atom) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if found == nil && n.Type == html.ElementNode && n.DataAtom == tag {
+			found = n
+		}
+		return found == nil
+	})
+	return found
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockTags[child.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+// innerRawText is the text below n as written in the page
+func innerRawText(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(n *html.Node) bool {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+// innerText is the text below n with its whitespace collapsed
+func innerText(n *html.Node) string {
+	return strings.Join(strings.Fields(innerRawText(n)), " ")
+}
+
+// linkDensity is the share of the text below n that is inside links
+func linkDensity(n *html.Node) float64 {
+	textLength := len(innerText(n))
+	if textLength == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	walk(n, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			linkLength += len(innerText(n))
+			return false
+		}
+		return true
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package readability
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Rates rise again | The Daily Example</title>
+	<meta property="og:title" content="Rates rise again">
+	<script>trackPageView();</script>
+	<style>.story { font-family: serif; }</style>
+</head>
+<body>
+	<header class="masthead"><a href="/">The Daily Example</a></header>
+	<nav><ul><li><a href="/world">World</a></li><li><a href="/business">Business</a></li></ul></nav>
+	<div class="cookie-banner">We use cookies, <a href="/privacy">read more</a>.</div>
+	<main>
+		<article class="story">
+			<h1>Rates rise again</h1>
+			<div class="share-buttons"><a href="https://social.example/share">Share</a></div>
+			<div class="story-body">
+				<p>The central bank raised its benchmark rate on Wednesday, the third increase this year, citing stubborn inflation in housing, energy and services.</p>
+				<div class="ad-slot advert">Buy one, get one free</div>
+				<p>Officials said further increases were possible, although several members argued for a pause, pointing to slowing hiring and weaker retail sales.</p>
+				<figure><img data-src="/images/chart.png" src="/images/placeholder.gif" alt="Rate chart"><figcaption>The benchmark rate since 2020</figcaption></figure>
+				<p>Markets had expected the move, and <a href="/markets/today">stocks closed</a> slightly higher after the announcement.</p>
+			</div>
+			<ul class="related-links">
+				<li><a href="/a">Five things to know about rates</a></li>
+				<li><a href="/b">What a rate rise means for your mortgage</a></li>
+			</ul>
+		</article>
+	</main>
+	<section id="comments"><p>First! This is a comment that is long enough to be scored, with commas, commas, commas.</p></section>
+	<footer><p>Copyright 2026 The Daily Example, all rights reserved, no reproduction.</p></footer>
+</body>
+</html>`
+
+func TestExtract(t *testing.T) {
+	pageURL, _ := url.Parse("https://news.example/business/rates-rise-again")
+	article, err := Extract(strings.NewReader(articlePage), pageURL)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if article.Title != "Rates rise again" {
+		t.Errorf("Title = %q, want the og:title", article.Title)
+	}
+
+	wantText := []string{
+		"The central bank raised its benchmark rate on Wednesday",
+		"Officials said further increases were possible",
+		"The benchmark rate since 2020",
+		"Markets had expected the move, and stocks closed slightly higher",
+	}
+	for _, want := range wantText {
+		if !strings.Contains(article.Text, want) {
+			t.Errorf("Text is missing %q:\n%s", want, article.Text)
+		}
+	}
+
+	unwanted := []string{"Business", "cookies", "Share", "Buy one", "Five things", "First!", "Copyright", "trackPageView", "font-family"}
+	for _, junk := range unwanted {
+		if strings.Contains(article.Text, junk) || strings.Contains(article.HTML, junk) {
+			t.Errorf("page furniture %q was kept:\nText: %s\nHTML: %s", junk, article.Text, article.HTML)
+		}
+	}
+
+	wantHTML := []string{
+		`<a href="https://news.example/markets/today">stocks closed</a>`,
+		`<img src="https://news.example/images/chart.png" alt="Rate chart"/>`,
+		`<p>The central bank raised`,
+	}
+	for _, want := range wantHTML {
+		if !strings.Contains(article.HTML, want) {
+			t.Errorf("HTML is missing %q:\n%s", want, article.HTML)
+		}
+	}
+	if strings.Contains(article.HTML, "class=") {
+		t.Errorf("HTML kept the page's classes:\n%s", article.HTML)
+	}
+
+	if !strings.Contains(article.Text, "this year, citing stubborn inflation in housing, energy and services.\n\nOfficials said") {
+		t.Errorf("paragraphs aren't separated by a blank line:\n%s", article.Text)
+	}
+}
+
+func TestExtract_noContent(t *testing.T) {
+	page := `<html><body><nav><a href="/">Home</a></nav><script>app()</script></body></html>`
+	_, err := Extract(strings.NewReader(page), nil)
+	if !errors.Is(err, ErrNoContent) {
+		t.Errorf("Extract() error = %v, want ErrNoContent", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package schedule decides when agg should poll a feed next.
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// Bounds clamps every interval the scheduler hands out
+type Bounds struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (b Bounds) Clamp(d time.Duration) time.Duration {
+	if b.Min > 0 && d < b.Min {
+		return b.Min
+	}
+	if b.Max > 0 && d > b.Max {
+		return b.Max
+	}
+	return d
+}
+
+// Number of most recent posts looked at when estimating how often a feed posts
+const HistorySize = 10
+
+// A feed that returned nothing new waits this much longer on the next round
+const decayFactor = 1.5
+
+// AdaptiveInterval returns the wait before the next fetch of a feed: half the mean gap between
+// its recent posts after a fetch with new items, or current grown by decayFactor after one
+// without. Without enough history it keeps current. The result is always clamped to bounds.
+func AdaptiveInterval(current time.Duration, published []time.Time, newItems int, bounds Bounds) time.Duration {
+	next := current
+
+	if newItems == 0 {
+		next = time.Duration(float64(current) * decayFactor)
+	} else if gap, ok := MeanGap(published, HistorySize); ok {
+		next = gap / 2
+	}
+
+	return bounds.Clamp(next)
+}
+
+// MeanGap returns the average time between the n most recent publish times
+func MeanGap(published []time.Time, n int) (time.Duration, bool) {
+	times := make([]time.Time, 0, len(published))
+	for _, t := range published {
+		if !t.IsZero() {
+			times = append(times, t)
+		}
+	}
+	if len(times) < 2 {
+		return 0, false
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+	if len(times) > n {
+		times = times[:n]
+	}
+
+	span := times[0].Sub(times[len(times)-1])
+	if span <= 0 {
+		return 0, false
+	}
+
+	return span / time.Duration(len(times)-1), true
+}
@@ -0,0 +1,100 @@
+package schedule
+
+import (
+	"strings"
+	"time"
+)
+
+// Hints are the publisher's own scheduling hints from the feed document:
+// RSS <ttl>, <skipHours>, <skipDays> and the Syndication module's sy:updatePeriod / sy:updateFrequency
+type Hints struct {
+	TTL             time.Duration
+	SkipHours       []int
+	SkipDays        []time.Weekday
+	UpdatePeriod    string
+	UpdateFrequency int
+}
+
+// MinInterval is the shortest interval the publisher asks us to wait between two polls
+func (h Hints) MinInterval() time.Duration {
+	min := h.TTL
+
+	if period, ok := updatePeriods[strings.ToLower(strings.TrimSpace(h.UpdatePeriod))]; ok {
+		frequency := h.UpdateFrequency
+		if frequency < 1 {
+			frequency = 1
+		}
+		if syndication := period / time.Duration(frequency); syndication > min {
+			min = syndication
+		}
+	}
+
+	return min
+}
+
+var updatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// NextAllowed returns the first moment at or after t that is not inside a skipped hour or day.
+// RSS defines skipHours and skipDays in GMT.
+func (h Hints) NextAllowed(t time.Time) time.Time {
+	if len(h.SkipHours) == 0 && len(h.SkipDays) == 0 {
+		return t
+	}
+
+	// a week of hours is enough to find a free slot unless every hour is skipped
+	for i := 0; i < 7*24; i++ {
+		gmt := t.UTC()
+		if !h.skipsHour(gmt.Hour()) && !h.skipsDay(gmt.Weekday()) {
+			return t
+		}
+		t = gmt.Truncate(time.Hour).Add(time.Hour)
+	}
+
+	return t
+}
+
+func (h Hints) skipsHour(hour int) bool {
+	for _, skip := range h.SkipHours {
+		// RSS 2.0 allows 0-23, some publishers write 24 for midnight
+		if skip%24 == hour {
+			return true
+		}
+	}
+	return false
+}
+
+func (h Hints) skipsDay(day time.Weekday) bool {
+	for _, skip := range h.SkipDays {
+		if skip == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWeekday understands the day names used by <skipDays>, like "Monday"
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == name {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Apply combines the adaptive interval with the publisher's hints and returns
+// the interval to store and the time the feed is due next
+func (h Hints) Apply(now time.Time, interval time.Duration) (time.Duration, time.Time) {
+	if min := h.MinInterval(); interval < min {
+		interval = min
+	}
+
+	return interval, h.NextAllowed(now.Add(interval))
+}
@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily window in local time during which nothing is fetched.
+// The window may wrap around midnight, like "22:00-07:00".
+type QuietHours struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// ParseQuietHours parses a window written as "HH:MM-HH:MM"
+func ParseQuietHours(value string) (*QuietHours, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("quiet hours %q must look like 22:00-07:00", value)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start of quiet hours %q: %w", value, err)
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid end of quiet hours %q: %w", value, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("quiet hours %q start and end at the same time", value)
+	}
+
+	return &QuietHours{start: start, end: end}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (q *QuietHours) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return clock(q.start) + "-" + clock(q.end)
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// Contains reports whether t falls inside the quiet window
+func (q *QuietHours) Contains(t time.Time) bool {
+	now := sinceMidnight(t)
+	if q.start < q.end {
+		return now >= q.start && now < q.end
+	}
+	return now >= q.start || now < q.end
+}
+
+// End returns the moment the quiet window containing t is over, or t itself when t is outside it
+func (q *QuietHours) End(t time.Time) time.Time {
+	if !q.Contains(t) {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := midnight.Add(q.end)
+	if !end.After(t) {
+		end = midnight.AddDate(0, 0, 1).Add(q.end)
+	}
+	return end
+}
+
+// Policy is a user defined schedule for a feed or for the whole agg daemon
+type Policy struct {
+	Cron  *Cron
+	Quiet *QuietHours
+}
+
+// Next moves t forward to the first moment the policy allows a fetch.
+// A cron match can land in quiet hours and the end of quiet hours can miss the cron, so alternate a few times.
+func (p Policy) Next(t time.Time) time.Time {
+	for i := 0; i < 10; i++ {
+		moved := t
+		if p.Cron != nil && !p.Cron.matches(moved) {
+			next := p.Cron.Next(moved)
+			if next.IsZero() {
+				return t
+			}
+			moved = next
+		}
+		if p.Quiet != nil {
+			moved = p.Quiet.End(moved)
+		}
+		if moved.Equal(t) {
+			return t
+		}
+		t = moved
+	}
+	return t
+}
@@ -0,0 +1,164 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveInterval(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	hourly := []time.Time{now, now.Add(-time.Hour), now.Add(-2 * time.Hour), now.Add(-3 * time.Hour)}
+	twiceAYear := []time.Time{now, now.AddDate(0, -6, 0)}
+	bounds := Bounds{Min: 15 * time.Minute, Max: 24 * time.Hour}
+
+	type args struct {
+		current   time.Duration
+		published []time.Time
+		newItems  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{"news wire polls twice per gap", args{time.Hour, hourly, 1}, 30 * time.Minute},
+		{"rare feed is capped at max", args{time.Hour, twiceAYear, 1}, 24 * time.Hour},
+		{"nothing new decays", args{time.Hour, hourly, 0}, 90 * time.Minute},
+		{"decay stops at max", args{20 * time.Hour, hourly, 0}, 24 * time.Hour},
+		{"no history keeps current", args{time.Hour, []time.Time{now}, 1}, time.Hour},
+		{"fast feed is held at min", args{time.Hour, []time.Time{now, now.Add(-time.Minute)}, 1}, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AdaptiveInterval(tt.args.current, tt.args.published, tt.args.newItems, bounds); got != tt.want {
+				t.Errorf("AdaptiveInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHints_Apply(t *testing.T) {
+	monday9 := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		hints        Hints
+		interval     time.Duration
+		wantInterval time.Duration
+		wantNext     time.Time
+	}{
+		{"no hints", Hints{}, time.Hour, time.Hour, monday9.Add(time.Hour)},
+		{"ttl raises interval", Hints{TTL: 2 * time.Hour}, time.Hour, 2 * time.Hour, monday9.Add(2 * time.Hour)},
+		{"ttl below interval is ignored", Hints{TTL: time.Minute}, time.Hour, time.Hour, monday9.Add(time.Hour)},
+		{"update period and frequency", Hints{UpdatePeriod: "daily", UpdateFrequency: 4}, time.Hour, 6 * time.Hour, monday9.Add(6 * time.Hour)},
+		{"skip hours", Hints{SkipHours: []int{10, 11}}, time.Hour, time.Hour, monday9.Add(3 * time.Hour)},
+		{"skip days", Hints{SkipDays: []time.Weekday{time.Monday}}, time.Hour, time.Hour, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInterval, gotNext := tt.hints.Apply(monday9, tt.interval)
+			if gotInterval != tt.wantInterval {
+				t.Errorf("Hints.Apply() interval = %v, want %v", gotInterval, tt.wantInterval)
+			}
+			if !gotNext.Equal(tt.wantNext) {
+				t.Errorf("Hints.Apply() next = %v, want %v", gotNext, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	// 2024-03-01 is a Friday
+	friday := time.Date(2024, time.March, 1, 22, 50, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every 15 minutes", "*/15 * * * *", friday, time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC)},
+		{"weekday office hours skip the weekend", "*/15 7-22 * * MON-FRI", friday, time.Date(2024, time.March, 4, 7, 0, 0, 0, time.UTC)},
+		{"list of hours", "30 6,18 * * *", friday, time.Date(2024, time.March, 2, 6, 30, 0, 0, time.UTC)},
+		{"sunday as 7", "0 9 * * 7", friday, time.Date(2024, time.March, 3, 9, 0, 0, 0, time.UTC)},
+		{"month names", "0 0 1 JAN *", friday, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Cron.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCron_Next_halfHourOffset(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"later the same day", "0 11 * * *", time.Date(2024, time.March, 1, 10, 45, 0, 0, kolkata), time.Date(2024, time.March, 1, 11, 0, 0, 0, kolkata)},
+		{"next morning after the weekend", "*/15 7-22 * * MON-FRI", time.Date(2024, time.March, 1, 23, 10, 0, 0, kolkata), time.Date(2024, time.March, 4, 7, 0, 0, 0, kolkata)},
+		{"every hour on the hour", "0 * * * *", time.Date(2024, time.March, 1, 10, 5, 0, 0, kolkata), time.Date(2024, time.March, 1, 11, 0, 0, 0, kolkata)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Cron.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron_invalid(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "* * * * FUNDAY"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
+
+func TestPolicy_Next(t *testing.T) {
+	quiet, err := ParseQuietHours("22:00-07:00")
+	if err != nil {
+		t.Fatalf("ParseQuietHours() error = %v", err)
+	}
+	cron, err := ParseCron("0 * * * *")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		from   time.Time
+		want   time.Time
+	}{
+		{"outside quiet hours", Policy{Quiet: quiet}, time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)},
+		{"before midnight", Policy{Quiet: quiet}, time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC), time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC)},
+		{"after midnight", Policy{Quiet: quiet}, time.Date(2024, time.March, 2, 3, 0, 0, 0, time.UTC), time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC)},
+		{"cron and quiet hours", Policy{Cron: cron, Quiet: quiet}, time.Date(2024, time.March, 1, 21, 10, 0, 0, time.UTC), time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Policy.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
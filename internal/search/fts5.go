@@ -0,0 +1,97 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TSQueryToFTS5 rewrites a tsquery built by ToTSQuery for the SQLite FTS5 MATCH operator.
+// Every word is quoted, so FTS5 keywords and punctuation never need escaping. FTS5 NOT is
+// binary, so the excluded terms of a group go after its wanted terms:
+//
+//	!sports & election -> "election" NOT "sports"
+//
+// OR groups keep their meaning because & binds tighter than | in both syntaxes.
+func TSQueryToFTS5(tsquery string) (string, error) {
+	groups, err := parseTSQuery(tsquery)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered []string
+	for _, group := range groups {
+		var wanted []string
+		for _, term := range group.wanted {
+			wanted = append(wanted, term.fts5())
+		}
+
+		r := strings.Join(wanted, " AND ")
+		if len(group.excluded) > 0 && len(wanted) > 1 {
+			r = "(" + r + ")"
+		}
+		for _, term := range group.excluded {
+			r += " NOT " + term.fts5()
+		}
+		rendered = append(rendered, r)
+	}
+
+	if len(rendered) > 1 {
+		for i, group := range rendered {
+			if strings.Contains(group, " AND ") || strings.Contains(group, " NOT ") {
+				rendered[i] = "(" + group + ")"
+			}
+		}
+	}
+	return strings.Join(rendered, " OR "), nil
+}
+
+// tsGroup is one side of an OR in a tsquery, a document matches it when it has every
+// wanted term and none of the excluded ones
+type tsGroup struct {
+	wanted   []tsTerm
+	excluded []tsTerm
+}
+
+// tsTerm is a single word or a phrase, prefix applies to its last word
+type tsTerm struct {
+	words  []string
+	prefix bool
+}
+
+func (t tsTerm) fts5() string {
+	rendered := `"` + strings.Join(t.words, " ") + `"`
+	if t.prefix {
+		rendered += "*"
+	}
+	return rendered
+}
+
+// parseTSQuery splits a tsquery built by ToTSQuery back into its OR groups
+func parseTSQuery(tsquery string) ([]tsGroup, error) {
+	var groups []tsGroup
+	for _, part := range strings.Split(tsquery, " | ") {
+		var group tsGroup
+		for _, raw := range strings.Split(part, " & ") {
+			negated := strings.HasPrefix(raw, "!")
+			raw = strings.TrimPrefix(raw, "!")
+			term := tsTerm{prefix: strings.HasSuffix(raw, ":*")}
+			raw = strings.TrimSuffix(raw, ":*")
+			raw = strings.TrimSuffix(strings.TrimPrefix(raw, "("), ")")
+
+			for _, w := range strings.Split(raw, " <-> ") {
+				term.words = append(term.words, cleanWord(w))
+			}
+
+			if negated {
+				group.excluded = append(group.excluded, term)
+			} else {
+				group.wanted = append(group.wanted, term)
+			}
+		}
+		if len(group.wanted) == 0 {
+			return nil, fmt.Errorf("every part of an OR search needs at least one word to look for")
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
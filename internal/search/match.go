@@ -0,0 +1,143 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Matcher evaluates a tsquery built by ToTSQuery in Go, for storage that has no full-text
+// index. Text is split into lower case words the same way query words are cleaned. Words are
+// compared as written, without stemming, so "rate" doesn't find "rates" but "rate*" does. A
+// phrase must match consecutive words within one field.
+type Matcher struct {
+	groups []tsGroup
+}
+
+func NewMatcher(tsquery string) (*Matcher, error) {
+	groups, err := parseTSQuery(tsquery)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{groups: groups}, nil
+}
+
+// Match reports whether any OR group matches the fields of a document
+func (m *Matcher) Match(fields ...string) bool {
+	docs := make([][]string, len(fields))
+	for i, field := range fields {
+		docs[i] = Words(field)
+	}
+
+	for _, group := range m.groups {
+		if group.matches(docs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hits counts how often the wanted terms occur in text
+func (m *Matcher) Hits(text string) int {
+	words := Words(text)
+	hits := 0
+	for _, group := range m.groups {
+		for _, term := range group.wanted {
+			hits += len(term.positions(words))
+		}
+	}
+	return hits
+}
+
+// Highlight returns up to maxWords words of text starting shortly before the first hit,
+// with every word of a hit wrapped in **
+func (m *Matcher) Highlight(text string, maxWords int) string {
+	fields := strings.Fields(text)
+	words := make([]string, len(fields))
+	for i, f := range fields {
+		words[i] = cleanWord(f)
+	}
+
+	marked := make([]bool, len(fields))
+	first := -1
+	for _, group := range m.groups {
+		for _, term := range group.wanted {
+			for _, pos := range term.positions(words) {
+				for i := pos; i < pos+len(term.words); i++ {
+					marked[i] = true
+				}
+				if first < 0 || pos < first {
+					first = pos
+				}
+			}
+		}
+	}
+
+	start := 0
+	if first > 3 {
+		start = first - 3
+	}
+	end := min(start+maxWords, len(fields))
+
+	var out []string
+	for i := start; i < end; i++ {
+		if marked[i] {
+			out = append(out, "**"+fields[i]+"**")
+		} else {
+			out = append(out, fields[i])
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func (g tsGroup) matches(docs [][]string) bool {
+	found := func(term tsTerm) bool {
+		for _, words := range docs {
+			if len(term.positions(words)) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, term := range g.wanted {
+		if !found(term) {
+			return false
+		}
+	}
+	for _, term := range g.excluded {
+		if found(term) {
+			return false
+		}
+	}
+	return true
+}
+
+// positions returns the index of every place in words where the term starts
+func (t tsTerm) positions(words []string) []int {
+	var positions []int
+	for start := 0; start+len(t.words) <= len(words); start++ {
+		matched := true
+		for i, w := range t.words {
+			candidate := words[start+i]
+			if t.prefix && i == len(t.words)-1 {
+				matched = strings.HasPrefix(candidate, w)
+			} else {
+				matched = candidate == w
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			positions = append(positions, start)
+		}
+	}
+	return positions
+}
+
+// Words splits text into lower case words of letters and digits
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
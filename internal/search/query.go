@@ -0,0 +1,156 @@
+// Package search turns the query syntax of the search command into a Postgres tsquery, or an FTS5 query for SQLite,
+// and can evaluate it in Go for the in-memory store.
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ToTSQuery turns a search typed by a user into a Postgres tsquery:
+//
+//	climate policy  -> climate & policy   every plain word must match
+//	"rate hike"     -> (rate <-> hike)    quoted words must appear in order
+//	migrat*         -> migrat:*           a trailing * matches any word prefix
+//	-sports         -> !sports            a leading - excludes the term
+//	go OR rust      -> go | rust          OR matches either term
+//
+// Everything except letters and digits is dropped from the words, so the result is always a valid tsquery.
+func ToTSQuery(query string) (string, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	positive := false
+	pendingOr := false
+	for _, tok := range tokens {
+		if tok.or {
+			if b.Len() == 0 {
+				return "", fmt.Errorf("OR needs a term on both sides")
+			}
+			pendingOr = true
+			continue
+		}
+
+		term := tok.render()
+		if term == "" {
+			continue
+		}
+		if !tok.negated {
+			positive = true
+		}
+
+		if b.Len() > 0 {
+			if pendingOr {
+				b.WriteString(" | ")
+			} else {
+				b.WriteString(" & ")
+			}
+		}
+		pendingOr = false
+		b.WriteString(term)
+	}
+
+	if pendingOr {
+		return "", fmt.Errorf("OR needs a term on both sides")
+	}
+	if !positive {
+		return "", fmt.Errorf("the search query needs at least one word to look for")
+	}
+
+	return b.String(), nil
+}
+
+type token struct {
+	words   []string
+	phrase  bool
+	prefix  bool
+	negated bool
+	or      bool
+}
+
+func (t token) render() string {
+	var words []string
+	for _, w := range t.words {
+		if w = cleanWord(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	term := words[0]
+	if len(words) > 1 {
+		term = "(" + strings.Join(words, " <-> ") + ")"
+	}
+	if t.prefix {
+		term += ":*"
+	}
+	if t.negated {
+		term = "!" + term
+	}
+	return term
+}
+
+func cleanWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, word)
+}
+
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	rest := strings.TrimSpace(query)
+
+	for rest != "" {
+		var tok token
+		if strings.HasPrefix(rest, "-") {
+			tok.negated = true
+			rest = rest[1:]
+		}
+
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.Index(rest[1:], "\"")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in the search query")
+			}
+			tok.phrase = true
+			tok.words = strings.Fields(rest[1 : end+1])
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			word := rest[:end]
+			rest = rest[end:]
+
+			if word == "OR" && !tok.negated {
+				tok.or = true
+			} else {
+				if strings.HasSuffix(word, "*") {
+					tok.prefix = true
+					word = strings.TrimRight(word, "*")
+				}
+				tok.words = []string{word}
+			}
+		}
+
+		if strings.HasPrefix(rest, "*") && tok.phrase {
+			tok.prefix = true
+			rest = rest[1:]
+		}
+
+		tokens = append(tokens, tok)
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,115 @@
+package search
+
+import "testing"
+
+func TestToTSQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"words", "climate policy", "climate & policy", false},
+		{"phrase", `"rate hike" fed`, "(rate <-> hike) & fed", false},
+		{"prefix", "migrat*", "migrat:*", false},
+		{"negation", "election -sports", "election & !sports", false},
+		{"or", "go OR rust", "go | rust", false},
+		{"negated phrase", `apple -"apple watch"`, "apple & !(apple <-> watch)", false},
+		{"punctuation is dropped", "c++ o'reilly", "c & oreilly", false},
+		{"only negation", "-sports", "", true},
+		{"dangling or", "go OR", "", true},
+		{"unterminated quote", `"rate hike`, "", true},
+		{"empty", "   ", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToTSQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToTSQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ToTSQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTSQueryToFTS5(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"words", "climate policy", `"climate" AND "policy"`, false},
+		{"phrase", `"rate hike" fed`, `"rate hike" AND "fed"`, false},
+		{"prefix", "migrat*", `"migrat"*`, false},
+		{"negation goes last", "-sports election", `"election" NOT "sports"`, false},
+		{"or", "go OR rust", `"go" OR "rust"`, false},
+		{"keywords are quoted", "NEAR not", `"near" AND "not"`, false},
+		{"or with groups", "a b OR c -d", `("a" AND "b") OR ("c" NOT "d")`, false},
+		{"or without a wanted word", "go OR -rust", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tsquery, err := ToTSQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ToTSQuery() error = %v", err)
+			}
+			got, err := TSQueryToFTS5(tsquery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TSQueryToFTS5() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("TSQueryToFTS5() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		fields []string
+		want   bool
+	}{
+		{"words in different fields", "climate policy", []string{"Climate summit", "A new policy"}, true},
+		{"missing word", "climate policy", []string{"Climate summit", "Nothing else"}, false},
+		{"phrase in order", `"rate hike"`, []string{"The Fed's rate hike, explained"}, true},
+		{"phrase out of order", `"rate hike"`, []string{"A hike in the rate"}, false},
+		{"prefix", "migrat*", []string{"Bird migration"}, true},
+		{"no stemming", "migrate", []string{"Bird migration"}, false},
+		{"excluded word", "election -sports", []string{"Election night", "sports results"}, false},
+		{"either side of or", "go OR rust", []string{"Rust 2024 edition"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tsquery, err := ToTSQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ToTSQuery() error = %v", err)
+			}
+			m, err := NewMatcher(tsquery)
+			if err != nil {
+				t.Fatalf("NewMatcher() error = %v", err)
+			}
+			if got := m.Match(tt.fields...); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Highlight(t *testing.T) {
+	m, err := NewMatcher("(rate <-> hike) & fed")
+	if err != nil {
+		t.Fatalf("NewMatcher() error = %v", err)
+	}
+	got := m.Highlight("Markets moved a lot today after the surprise rate hike by the Fed.", 8)
+	want := "after the surprise **rate** **hike** by the **Fed.**"
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+	gooseDatabase "github.com/pressly/goose/v3/database"
+)
+
+// The goose files in sql/schema and sql/sqlite/schema are compiled into the binary,
+// so a release carries its own schema
+//
+//go:embed sql/schema/*.sql sql/sqlite/schema/*.sql
+var schemaFiles embed.FS
+
+func newMigrationProvider(db *sql.DB, dialect goose.Dialect) (*goose.Provider, error) {
+	dir := "sql/schema"
+	if dialect == goose.DialectSQLite3 {
+		dir = "sql/sqlite/schema"
+	}
+	migrations, err := fs.Sub(schemaFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(dialect, db, migrations)
+	if err != nil {
+		return nil, fmt.Errorf("error in loading the schema migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// legacySchemaVersion finds how far a Postgres database made by the old setup_db.sql or
+// create_tables.sql scripts got, by which of the first four migrations have all their tables
+// and columns. It returns 0 when the database has none of them, and an error when it has a
+// later one without an earlier one, since no version would make goose apply just the gap.
+func legacySchemaVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	probes := []string{
+		"SELECT id FROM users WHERE false",
+		"SELECT id FROM feeds WHERE false",
+		"SELECT id FROM feed_follow WHERE false",
+		"SELECT last_fetched_at FROM feeds WHERE false",
+	}
+	applied := make([]bool, len(probes))
+	for i, probe := range probes {
+		rows, err := db.QueryContext(ctx, probe)
+		if err != nil {
+			continue
+		}
+		rows.Close()
+		applied[i] = true
+	}
+
+	var version int64
+	for version < int64(len(probes)) && applied[version] {
+		version++
+	}
+	for v := version + 1; v < int64(len(probes)); v++ {
+		if applied[v] {
+			return 0, fmt.Errorf("the database has migration %d but not migration %d, apply the missing part of the old scripts by hand before the baseline", v+1, version+1)
+		}
+	}
+	return version, nil
+}
+
+// hasVersionTable reports whether goose has ever run against the database
+func hasVersionTable(ctx context.Context, db *sql.DB) bool {
+	rows, err := db.QueryContext(ctx, "SELECT version_id FROM "+goose.DefaultTablename+" WHERE false")
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
+
+// needsBaseline reports whether the database was made by the old scripts and isn't adopted yet
+func needsBaseline(ctx context.Context, db *sql.DB, dialect goose.Dialect) bool {
+	if dialect != goose.DialectPostgres || hasVersionTable(ctx, db) {
+		return false
+	}
+	// a half-made schema needs one too, and `migrate baseline` explains what's missing
+	version, err := legacySchemaVersion(ctx, db)
+	return err != nil || version > 0
+}
+
+// baselineSchema records the migrations a database made by the old scripts already has as
+// applied, so `migrate up` carries on from there instead of creating the users table again
+func baselineSchema(ctx context.Context, db *sql.DB, dialect goose.Dialect) (int64, error) {
+	if dialect != goose.DialectPostgres {
+		return 0, fmt.Errorf("only a Postgres database made by the old setup scripts needs a baseline, run `migrate up`")
+	}
+	if hasVersionTable(ctx, db) {
+		return 0, fmt.Errorf("the database schema is already tracked by the migrations, run `migrate up`")
+	}
+
+	version, err := legacySchemaVersion(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if version == 0 {
+		return 0, fmt.Errorf("the database has no tables to adopt, run `migrate up` to create them")
+	}
+
+	store, err := gooseDatabase.NewStore(gooseDatabase.DialectPostgres, goose.DefaultTablename)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := store.CreateVersionTable(ctx, tx); err != nil {
+		return 0, fmt.Errorf("error in creating the migration version table: %w", err)
+	}
+	// goose records version 0 when it creates the table itself
+	for v := int64(0); v <= version; v++ {
+		if err := store.Insert(ctx, tx, gooseDatabase.InsertRequest{Version: v}); err != nil {
+			return 0, fmt.Errorf("error in recording migration %d as applied: %w", v, err)
+		}
+	}
+	return version, tx.Commit()
+}
+
+// checkSchema refuses to run commands against a database that is missing migrations of this build
+func checkSchema(ctx context.Context, db *sql.DB, dialect goose.Dialect) error {
+	provider, err := newMigrationProvider(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	if needsBaseline(ctx, db, dialect) {
+		return fmt.Errorf("the database was made by the old setup scripts, run `migrate baseline` and then `migrate up`")
+	}
+
+	pending, err := provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("error in checking the database schema, a new database needs `migrate up` first: %w", err)
+	}
+	if !pending {
+		return nil
+	}
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("error in reading the database schema version: %w", err)
+	}
+	return fmt.Errorf("the database schema is at version %d but this build needs version %d, run `migrate up` first", current, target)
+}
+
+// handlerMigrate applies, rolls back or lists the embedded schema migrations, or adopts a
+// database made before the migrations were embedded
+func handlerMigrate(s *state, cmd command) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("enter the migrate command along with up, down, status, redo or baseline")
+	}
+	if s.ephemeral {
+		return fmt.Errorf("an --ephemeral session has no database schema to migrate")
+	}
+
+	provider, err := newMigrationProvider(s.conn, s.dialect)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch cmd.args[0] {
+	case "up":
+		if needsBaseline(ctx, s.conn, s.dialect) {
+			return fmt.Errorf("the database was made by the old setup scripts, run `migrate baseline` first")
+		}
+		results, err := provider.Up(ctx)
+		for _, result := range results {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("error in applying the migrations: %w", err)
+		}
+		if len(results) == 0 {
+			fmt.Println("The database schema is up to date")
+		}
+
+	case "down":
+		result, err := provider.Down(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("error in rolling back the last migration: %w", err)
+		}
+
+	case "redo":
+		// roll back the last migration and apply it again, handy while writing a migration
+		result, err := provider.Down(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("error in rolling back the last migration: %w", err)
+		}
+		result, err = provider.UpByOne(ctx)
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("error in applying the migration again: %w", err)
+		}
+
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("error in reading the migration status: %w", err)
+		}
+		for _, status := range statuses {
+			if status.State == goose.StateApplied {
+				fmt.Printf("%-8s %s (%s)\n", status.State, status.Source.Path, status.AppliedAt.Format("2006-01-02 15:04"))
+			} else {
+				fmt.Printf("%-8s %s\n", status.State, status.Source.Path)
+			}
+		}
+
+	case "baseline":
+		version, err := baselineSchema(ctx, s.conn, s.dialect)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded migrations up to version %d as applied, run `migrate up` for the rest\n", version)
+
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q, use up, down, status, redo or baseline", cmd.args[0])
+	}
+
+	return nil
+}
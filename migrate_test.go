@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/config"
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+// newSQLiteState returns a state on a new SQLite file, without any migrations applied
+func newSQLiteState(t *testing.T) *state {
+	t.Helper()
+	q, db, dialect, err := openDatabase("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("openDatabase() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &state{db: q, conn: db, dialect: dialect, config: &config.Config{}}
+}
+
+// newSQLiteTestState is newTestState on a migrated SQLite file
+func newSQLiteTestState(t *testing.T) (*state, database.User) {
+	t.Helper()
+	s := newSQLiteState(t)
+	migrate(t, s, "up")
+	user, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserName:  "alice",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	return s, user
+}
+
+func migrate(t *testing.T, s *state, subcommand string) string {
+	t.Helper()
+	output, err := captureOutput(t, func() error {
+		return handlerMigrate(s, command{name: "migrate", args: []string{subcommand}})
+	})
+	if err != nil {
+		t.Fatalf("migrate %s error = %v", subcommand, err)
+	}
+	return output
+}
+
+func Test_handlerMigrate(t *testing.T) {
+	s := newSQLiteState(t)
+	ctx := context.Background()
+
+	if err := checkSchema(ctx, s.conn, s.dialect); err == nil {
+		t.Fatalf("checkSchema() on a new database error = nil, want migrate up asked for")
+	}
+
+	migrate(t, s, "up")
+	provider, err := newMigrationProvider(s.conn, s.dialect)
+	if err != nil {
+		t.Fatalf("newMigrationProvider() error = %v", err)
+	}
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil || current != target {
+		t.Fatalf("after migrate up the schema is at %d of %d, %v", current, target, err)
+	}
+	if err := checkSchema(ctx, s.conn, s.dialect); err != nil {
+		t.Fatalf("checkSchema() after migrate up error = %v", err)
+	}
+	if output := migrate(t, s, "up"); !strings.Contains(output, "up to date") {
+		t.Errorf("second migrate up printed %q, want up to date", output)
+	}
+
+	status := migrate(t, s, "status")
+	if strings.Count(status, "applied") != int(target) || strings.Contains(status, "pending") {
+		t.Errorf("migrate status printed:\n%s\nwant all %d migrations applied", status, target)
+	}
+
+	migrate(t, s, "down")
+	if current, _, _ := provider.GetVersions(ctx); current != target-1 {
+		t.Errorf("after migrate down the schema is at %d, want %d", current, target-1)
+	}
+	if err := checkSchema(ctx, s.conn, s.dialect); err == nil || !strings.Contains(err.Error(), "migrate up") {
+		t.Errorf("checkSchema() with a pending migration error = %v, want migrate up asked for", err)
+	}
+	if status := migrate(t, s, "status"); strings.Count(status, "pending") != 1 {
+		t.Errorf("migrate status after down printed:\n%s\nwant one pending migration", status)
+	}
+
+	migrate(t, s, "up")
+	redo := migrate(t, s, "redo")
+	if strings.Count(redo, "OK") != 2 {
+		t.Errorf("migrate redo printed:\n%s\nwant the last migration rolled back and applied", redo)
+	}
+	if current, _, _ := provider.GetVersions(ctx); current != target {
+		t.Errorf("after migrate redo the schema is at %d, want %d", current, target)
+	}
+
+	// the tables still work after the round trip
+	if _, err := s.db.GetFeeds(ctx); err != nil {
+		t.Errorf("GetFeeds() after redo error = %v", err)
+	}
+
+	err = handlerMigrate(s, command{name: "migrate", args: []string{"baseline"}})
+	if err == nil || !strings.Contains(err.Error(), "Postgres") {
+		t.Errorf("migrate baseline on SQLite error = %v, want it refused", err)
+	}
+}
+
+func Test_legacySchemaVersion(t *testing.T) {
+	s := newSQLiteState(t)
+	ctx := context.Background()
+
+	if version, err := legacySchemaVersion(ctx, s.conn); err != nil || version != 0 {
+		t.Errorf("legacySchemaVersion() of an empty database = %d, %v, want 0", version, err)
+	}
+
+	// the tables the old create_tables.sql made
+	oldScript := []string{
+		`CREATE TABLE users(id UUID PRIMARY KEY, created_at TIMESTAMP NOT NULL, updated_at TIMESTAMP NOT NULL, user_name varchar(255) NOT NULL UNIQUE)`,
+		`CREATE TABLE feeds(id UUID PRIMARY KEY, createdAt TIMESTAMP NOT NULL, updatedAt TIMESTAMP NOT NULL, feed_name VARCHAR(255) NOT NULL, feed_url VARCHAR(255) UNIQUE NOT NULL, user_id UUID NOT NULL)`,
+	}
+	for _, stmt := range oldScript {
+		if _, err := s.conn.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("ExecContext() error = %v", err)
+		}
+	}
+	if version, err := legacySchemaVersion(ctx, s.conn); err != nil || version != 2 {
+		t.Errorf("legacySchemaVersion() of the old script's tables = %d, %v, want 2", version, err)
+	}
+	if hasVersionTable(ctx, s.conn) {
+		t.Errorf("hasVersionTable() = true before any migration ran")
+	}
+
+	if _, err := s.conn.ExecContext(ctx, `ALTER TABLE feeds ADD COLUMN last_fetched_at TIMESTAMP`); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	// goose would apply 003 and then fail on 004's column, so there's no version to record
+	if _, err := legacySchemaVersion(ctx, s.conn); err == nil || !strings.Contains(err.Error(), "migration 4 but not migration 3") {
+		t.Errorf("legacySchemaVersion() without feed_follow error = %v, want one naming migration 3", err)
+	}
+	if _, err := s.conn.ExecContext(ctx, `CREATE TABLE feed_follow(id UUID PRIMARY KEY, user_id UUID NOT NULL, feed_id UUID NOT NULL)`); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+	if version, err := legacySchemaVersion(ctx, s.conn); err != nil || version != 4 {
+		t.Errorf("legacySchemaVersion() with every early table = %d, %v, want 4", version, err)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/dedup"
+	"github.com/Pradhyumna789/RSS/internal/search"
+	"github.com/google/uuid"
+)
+
+// savePosts stores the items of a feed and returns how many were new.
+// An item that another feed already delivered, judged by canonical url or content fingerprint,
+// is stored as a duplicate pointing at the first copy so browse shows the story once.
+// An item whose guid is already stored for the feed updates that post instead, see reviseKnownPost,
+// and an item that prune removed is skipped.
+func savePosts(ctx context.Context, s *state, feedID uuid.UUID, items []RSSItem) (int, error) {
+	newItems := 0
+
+	for _, item := range items {
+		if item.Link == "" {
+			continue
+		}
+
+		known, err := reviseKnownPost(ctx, s, feedID, item)
+		if err != nil {
+			return newItems, err
+		}
+		if known {
+			continue
+		}
+
+		pruned, err := s.db.IsPostPruned(ctx, database.IsPostPrunedParams{
+			FeedID: feedID,
+			Url:    item.Link,
+			Guid:   item.GUID,
+		})
+		if err != nil {
+			return newItems, fmt.Errorf("error in checking whether the post %s was pruned: %w", item.Link, err)
+		}
+		if pruned {
+			continue
+		}
+
+		canonicalURL := dedup.CanonicalURL(item.Link)
+		fingerprint := dedup.Fingerprint(item.Title, item.Description, item.Content)
+
+		var canonicalPostID uuid.NullUUID
+		canonicalID, err := s.db.FindCanonicalPost(ctx, database.FindCanonicalPostParams{
+			FeedID:       feedID,
+			CanonicalUrl: canonicalURL,
+			Fingerprint:  fingerprint,
+		})
+		if err == nil {
+			canonicalPostID = uuid.NullUUID{UUID: canonicalID, Valid: true}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return newItems, fmt.Errorf("error in looking for a duplicate of the post: %w", err)
+		}
+
+		var publishedAt sql.NullTime
+		if t, ok := parsePubDate(item.PubDate); ok {
+			publishedAt = sql.NullTime{Time: t, Valid: true}
+		}
+
+		created, err := s.db.CreatePost(ctx, database.CreatePostParams{
+			ID:              uuid.New(),
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+			Title:           item.Title,
+			Url:             item.Link,
+			Description:     sql.NullString{String: item.Description, Valid: item.Description != ""},
+			PublishedAt:     publishedAt,
+			FeedID:          feedID,
+			CanonicalUrl:    canonicalURL,
+			Fingerprint:     fingerprint,
+			CanonicalPostID: canonicalPostID,
+			Content:         sql.NullString{String: item.Content, Valid: item.Content != ""},
+			Author:          sql.NullString{String: itemAuthor(item), Valid: itemAuthor(item) != ""},
+			Guid:            sql.NullString{String: item.GUID, Valid: item.GUID != ""},
+		})
+		if err != nil {
+			return newItems, fmt.Errorf("error in saving the post %s: %w", item.Link, err)
+		}
+		newItems += int(created)
+
+		// The link was stored before guids were kept, from now on the post is found by its guid
+		if created == 0 && item.GUID != "" {
+			err = s.db.SetPostGUID(ctx, database.SetPostGUIDParams{
+				FeedID: feedID,
+				Url:    item.Link,
+				Guid:   sql.NullString{String: item.GUID, Valid: true},
+			})
+			if err != nil {
+				return newItems, fmt.Errorf("error in saving the guid of the post %s: %w", item.Link, err)
+			}
+		}
+	}
+
+	return newItems, nil
+}
+
+// itemAuthor prefers dc:creator, since RSS <author> is meant to be an email address
+func itemAuthor(item RSSItem) string {
+	if item.Creator != "" {
+		return strings.TrimSpace(item.Creator)
+	}
+	return strings.TrimSpace(item.Author)
+}
+
+// handlerSearch runs a full-text search over every stored post, see search.ToTSQuery for the query syntax
+func handlerSearch(s *state, cmd command) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	feedUrl := fs.String("feed", "", "only search the posts of this feed url")
+	since := fs.String("since", "", "only posts published on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "only posts published before this date (YYYY-MM-DD)")
+	author := fs.String("author", "", "only posts whose author contains this text")
+	limit := fs.Int("limit", 20, "maximum number of results")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the search flags (put -- before words that start with a dash): %w", err)
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("enter the search command along with the words to look for")
+	}
+
+	query, err := search.ToTSQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	params := database.SearchPostsParams{
+		Query:       query,
+		FeedUrl:     *feedUrl,
+		Author:      *author,
+		ResultLimit: int32(*limit),
+	}
+	if params.Since, err = parseDateFlag("since", *since); err != nil {
+		return err
+	}
+	if params.Until, err = parseDateFlag("until", *until); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	results, err := s.db.SearchPosts(ctx, params)
+	if err != nil {
+		return fmt.Errorf("error in searching the posts: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No posts found")
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Println("Title:", result.Title)
+		fmt.Println("Link:", result.Url)
+		fmt.Println("Feed:", result.FeedName)
+		if result.Author.Valid {
+			fmt.Println("Author:", result.Author.String)
+		}
+		if result.PublishedAt.Valid {
+			fmt.Println("Published:", result.PublishedAt.Time)
+		}
+		if result.Snippet != "" {
+			fmt.Println(result.Snippet)
+		}
+		fmt.Println("----------------------------------------")
+	}
+
+	return nil
+}
+
+func parseDateFlag(name, value string) (sql.NullTime, error) {
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("--%s must be a date like 2024-03-01: %w", name, err)
+	}
+
+	return sql.NullTime{Time: date, Valid: true}, nil
+}
+
+// handlerBrowse lists the latest unread posts of the feeds the user follows, --all includes read ones
+func handlerBrowse(s *state, cmd command, user database.User) error {
+	fs := flag.NewFlagSet("browse", flag.ContinueOnError)
+	includeRead := fs.Bool("all", false, "include posts you have already read")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the browse flags: %w", err)
+	}
+
+	limit := 2
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			return fmt.Errorf("the number of posts to browse must be a positive number")
+		}
+		limit = parsed
+	}
+
+	ctx := context.Background()
+	posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{
+		UserID:      user.ID,
+		IncludeRead: *includeRead,
+		PostLimit:   int32(limit),
+	})
+	if err != nil {
+		return fmt.Errorf("error in fetching the posts for the user: %w", err)
+	}
+
+	if len(posts) == 0 {
+		fmt.Println("No unread posts")
+		return nil
+	}
+
+	for _, post := range posts {
+		fmt.Println("Post ID:", post.ID)
+		if post.IsRead {
+			fmt.Println("Title:", post.Title, "(read)")
+		} else {
+			fmt.Println("Title:", post.Title)
+		}
+		fmt.Println("Link:", post.Url)
+		fmt.Println("Feed:", post.FeedName)
+		if post.AlsoIn != "" {
+			fmt.Println("Also in:", post.AlsoIn)
+		}
+		if post.PublishedAt.Valid {
+			fmt.Println("Published:", post.PublishedAt.Time)
+		}
+		if post.Description.Valid {
+			fmt.Println(post.Description.String)
+		}
+		fmt.Println("----------------------------------------")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func Test_handlerSearch(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	items := testItems(3)
+	items[0].Title, items[0].Description = "Climate talks stall", "Delegates left without a deal on climate change"
+	items[1].Title, items[1].Description = "Climate of the league", "The sports season opens"
+	items[2].Title, items[2].Description = "Climate change summit", "Ministers meet in the capital"
+	if _, err := savePosts(context.Background(), s, feed.ID, items); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+
+	// args as the shell hands them over, quotes already removed by the shell
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"negated word after --", []string{"--", "climate", "-sports"}, []string{"Climate talks stall", "Climate change summit"}},
+		{"flags then --", []string{"--limit", "5", "--", "climate", "-sports"}, []string{"Climate talks stall", "Climate change summit"}},
+		{"quoted query", []string{`"climate change" -sports`}, []string{"Climate talks stall", "Climate change summit"}},
+		{"phrase", []string{`"change summit"`}, []string{"Climate change summit"}},
+		{"flag after the words", []string{"climate", "--feed", feed.FeedUrl, "--limit", "1"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := captureOutput(t, func() error {
+				return handlerSearch(s, command{name: "search", args: tt.args})
+			})
+			if err != nil {
+				t.Fatalf("handlerSearch() error = %v", err)
+			}
+			if tt.want == nil {
+				if strings.Count(output, "Title:") != 1 {
+					t.Errorf("handlerSearch() printed:\n%s\nwant one result", output)
+				}
+				return
+			}
+			if got := strings.Count(output, "Title:"); got != len(tt.want) {
+				t.Errorf("handlerSearch() printed %d results, want %d:\n%s", got, len(tt.want), output)
+			}
+			for _, title := range tt.want {
+				if !strings.Contains(output, "Title: "+title+"\n") {
+					t.Errorf("handlerSearch() is missing %q:\n%s", title, output)
+				}
+			}
+		})
+	}
+
+	_, err := captureOutput(t, func() error {
+		return handlerSearch(s, command{name: "search", args: []string{"climate", "-sports"}})
+	})
+	if err == nil || !strings.Contains(err.Error(), "--") {
+		t.Errorf("handlerSearch() without -- error = %v, want a hint to use --", err)
+	}
+}
+
+func Test_savePosts_crossFeedDuplicates(t *testing.T) {
+	s, user := newTestState(t)
+	world := addTestFeed(t, s, user, "World", "https://world.example/rss")
+	markets := addTestFeed(t, s, user, "Markets", "https://markets.example/rss")
+	ctx := context.Background()
+
+	_, err := savePosts(ctx, s, world.ID, []RSSItem{
+		{Title: "Rates rise", Link: "https://world.example/rates", Description: "The central bank raised rates."},
+		{Title: "Daily briefing", Link: "https://world.example/briefing"},
+		{Title: "Bridge reopens", Link: "https://world.example/bridge"},
+	})
+	if err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+	_, err = savePosts(ctx, s, markets.ID, []RSSItem{
+		{Title: "Rates rise", Link: "https://markets.example/rates", Description: "The central bank raised rates."},
+		{Title: "Daily briefing", Link: "https://markets.example/briefing"},
+		{Title: "Bridge reopens", Link: "https://world.example/bridge?utm_source=markets"},
+	})
+	if err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+
+	for _, feed := range []database.Feed{world, markets} {
+		if err := handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user); err != nil {
+			t.Fatalf("handlerFollow() error = %v", err)
+		}
+	}
+	posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForUser() error = %v", err)
+	}
+	// a duplicate is folded into the World post and listed under "Also in"
+	var got []string
+	for _, post := range posts {
+		got = append(got, post.FeedName+": "+post.Title+" (also in: "+post.AlsoIn+")")
+	}
+	slices.Sort(got)
+	want := []string{
+		"Markets: Daily briefing (also in: )",      // only the title is the same
+		"World: Bridge reopens (also in: Markets)", // same canonical url
+		"World: Daily briefing (also in: )",
+		"World: Rates rise (also in: Markets)", // same title and description
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("posts in browse = %q, want %q", got, want)
+	}
+}
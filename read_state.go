@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+func parsePostID(cmd command) (uuid.UUID, error) {
+	if len(cmd.args) < 1 {
+		return uuid.UUID{}, fmt.Errorf("enter the %s command along with the id of the post", cmd.name)
+	}
+
+	postID, err := uuid.Parse(cmd.args[0])
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("%s is not a valid post id: %w", cmd.args[0], err)
+	}
+
+	return postID, nil
+}
+
+func handlerRead(s *state, cmd command, user database.User) error {
+	postID, err := parsePostID(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	marked, err := s.db.MarkPostRead(ctx, database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("error in marking the post as read: %w", err)
+	}
+
+	if marked == 0 {
+		fmt.Println("Post was already read or doesn't exist")
+		return nil
+	}
+
+	fmt.Println("Post marked as read")
+	return nil
+}
+
+func handlerUnread(s *state, cmd command, user database.User) error {
+	postID, err := parsePostID(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	marked, err := s.db.MarkPostUnread(ctx, database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("error in marking the post as unread: %w", err)
+	}
+
+	if marked == 0 {
+		fmt.Println("Post was not read")
+		return nil
+	}
+
+	fmt.Println("Post marked as unread")
+	return nil
+}
+
+// handlerMarkRead marks every post published before --before (default now) as read,
+// for one feed with --feed or for every feed the user follows
+func handlerMarkRead(s *state, cmd command, user database.User) error {
+	fs := flag.NewFlagSet("mark-read", flag.ContinueOnError)
+	feedUrl := fs.String("feed", "", "only mark the posts of this feed url")
+	before := fs.String("before", "", "only mark posts published before this date (YYYY-MM-DD)")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the mark-read flags: %w", err)
+	}
+
+	beforeDate, err := parseDateFlag("before", *before)
+	if err != nil {
+		return err
+	}
+	if !beforeDate.Valid {
+		beforeDate.Time = time.Now()
+	}
+
+	ctx := context.Background()
+	if *feedUrl != "" {
+		_, err := s.db.GetFeedByURL(ctx, *feedUrl)
+		if err != nil {
+			return fmt.Errorf("feed with URL %s not found: %w", *feedUrl, err)
+		}
+	}
+
+	marked, err := s.db.MarkPostsReadBefore(ctx, database.MarkPostsReadBeforeParams{
+		UserID:  user.ID,
+		Before:  beforeDate.Time,
+		FeedUrl: *feedUrl,
+	})
+	if err != nil {
+		return fmt.Errorf("error in marking the posts as read: %w", err)
+	}
+
+	fmt.Printf("%d posts marked as read\n", marked)
+	return nil
+}
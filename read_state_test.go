@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func Test_handlerReadState(t *testing.T) {
+	s, user := newTestState(t)
+	ctx := context.Background()
+	items := map[string][]RSSItem{
+		"https://a.example/rss": {
+			{Title: "A January", Link: "https://a.example/january", PubDate: "Mon, 01 Jan 2024 10:00:00 +0000"},
+			{Title: "A March", Link: "https://a.example/march", PubDate: "Fri, 01 Mar 2024 10:00:00 +0000"},
+		},
+		"https://b.example/rss": {
+			{Title: "B January", Link: "https://b.example/january", PubDate: "Mon, 15 Jan 2024 10:00:00 +0000"},
+		},
+	}
+	for feedURL, feedItems := range items {
+		feed := addTestFeed(t, s, user, feedURL, feedURL)
+		if err := handlerFollow(s, command{name: "follow", args: []string{feedURL}}, user); err != nil {
+			t.Fatalf("handlerFollow() error = %v", err)
+		}
+		if _, err := savePosts(ctx, s, feed.ID, feedItems); err != nil {
+			t.Fatalf("savePosts() error = %v", err)
+		}
+	}
+
+	unread := func() map[string]string {
+		t.Helper()
+		posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+		if err != nil {
+			t.Fatalf("GetPostsForUser() error = %v", err)
+		}
+		ids := map[string]string{}
+		for _, post := range posts {
+			ids[post.Title] = post.ID.String()
+		}
+		return ids
+	}
+	run := func(handler func(*state, command, database.User) error, name string, args ...string) string {
+		t.Helper()
+		output, err := captureOutput(t, func() error {
+			return handler(s, command{name: name, args: args}, user)
+		})
+		if err != nil {
+			t.Fatalf("%s %v error = %v", name, args, err)
+		}
+		return output
+	}
+	wantUnread := func(titles ...string) {
+		t.Helper()
+		var got []string
+		for title := range unread() {
+			got = append(got, title)
+		}
+		slices.Sort(got)
+		slices.Sort(titles)
+		if !reflect.DeepEqual(got, titles) {
+			t.Errorf("unread posts = %v, want %v", got, titles)
+		}
+	}
+
+	id := unread()["A March"]
+	if output := run(handlerRead, "read", id); !strings.Contains(output, "marked as read") {
+		t.Errorf("read = %q, want the post marked", output)
+	}
+	wantUnread("A January", "B January")
+	if output := run(handlerRead, "read", id); !strings.Contains(output, "already read") {
+		t.Errorf("second read = %q, want it reported as already read", output)
+	}
+	if output := run(handlerUnread, "unread", id); !strings.Contains(output, "marked as unread") {
+		t.Errorf("unread = %q, want the post marked", output)
+	}
+	wantUnread("A January", "A March", "B January")
+	if output := run(handlerUnread, "unread", id); !strings.Contains(output, "was not read") {
+		t.Errorf("second unread = %q, want it reported as not read", output)
+	}
+	if err := handlerRead(s, command{name: "read", args: []string{"not-an-id"}}, user); err == nil {
+		t.Errorf("read of an invalid id, want an error")
+	}
+
+	if output := run(handlerMarkRead, "mark-read", "--before", "2024-02-01", "--feed", "https://a.example/rss"); !strings.Contains(output, "1 posts marked") {
+		t.Errorf("mark-read --before --feed = %q, want only the older post of that feed", output)
+	}
+	wantUnread("A March", "B January")
+	if output := run(handlerMarkRead, "mark-read", "--before", "2024-02-01"); !strings.Contains(output, "1 posts marked") {
+		t.Errorf("mark-read --before = %q, want the older post of the other feed", output)
+	}
+	wantUnread("A March")
+	if output := run(handlerMarkRead, "mark-read"); !strings.Contains(output, "1 posts marked") {
+		t.Errorf("mark-read = %q, want everything up to now", output)
+	}
+	wantUnread()
+
+	if err := handlerMarkRead(s, command{name: "mark-read", args: []string{"--before", "yesterday"}}, user); err == nil {
+		t.Errorf("mark-read with an invalid date, want an error")
+	}
+	if err := handlerMarkRead(s, command{name: "mark-read", args: []string{"--feed", "https://missing.example/rss"}}, user); err == nil {
+		t.Errorf("mark-read of an unknown feed, want an error")
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	// how often a running agg daemon looks for feeds queued by refresh
+	refreshPollInterval = 10 * time.Second
+	// how often agg updates its row in agg_workers, and how old a row may get before the worker counts as gone
+	aggHeartbeatInterval = 30 * time.Second
+	aggHeartbeatTimeout  = 3 * aggHeartbeatInterval
+	// how long agg waits for the database while unregistering on the way out
+	aggCleanupTimeout = 10 * time.Second
+)
+
+// startAggHeartbeat registers the agg worker and keeps its row fresh until stop is called.
+// stop ends the heartbeat before removing the row, so a late beat can't bring the worker
+// back, and releases any feed leases the worker still holds.
+func startAggHeartbeat(ctx context.Context, s *state) (stop func(), err error) {
+	err = s.db.UpsertAggWorker(ctx, s.workerID)
+	if err != nil {
+		return nil, fmt.Errorf("error in registering the agg worker: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(aggHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.db.UpsertAggWorker(ctx, s.workerID); err != nil && !errors.Is(err, context.Canceled) {
+					fmt.Println("error in updating the agg worker heartbeat:", err)
+				}
+			}
+		}
+	}()
+
+	stop = func() {
+		cancel()
+		<-done
+
+		ctx, cancel := context.WithTimeout(context.Background(), aggCleanupTimeout)
+		defer cancel()
+		if err := s.db.DeleteAggWorker(ctx, s.workerID); err != nil {
+			fmt.Println("error in unregistering the agg worker:", err)
+		}
+		if err := s.db.ReleaseFeedLeases(ctx, sql.NullString{String: s.workerID, Valid: true}); err != nil {
+			fmt.Println("error in releasing the feed leases of the agg worker:", err)
+		}
+	}
+	return stop, nil
+}
+
+// handlerRefresh fetches feeds right away. If an agg daemon is running the feeds are queued
+// ahead of everything else for it instead, so the two never fetch the same feed.
+func handlerRefresh(s *state, cmd command) error {
+	fs := flag.NewFlagSet("refresh", flag.ContinueOnError)
+	all := fs.Bool("all", false, "refresh every active feed")
+	following := fs.Bool("following", false, "refresh the feeds the current user follows")
+	addHTTPCacheFlags(fs, s)
+	err := addFetchFlags(fs, s)
+	if err != nil {
+		return err
+	}
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the refresh flags: %w", err)
+	}
+	if err := checkReplay(s, "refresh"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	var requested int64
+	switch {
+	case *all:
+		requested, err = s.db.RequestAllFeedsRefresh(ctx)
+	case *following:
+		user, userErr := s.db.GetUser(ctx, s.config.CurrentUserName)
+		if userErr != nil {
+			return fmt.Errorf("user doesn't exist: %w", userErr)
+		}
+		requested, err = s.db.RequestFollowedFeedsRefresh(ctx, user.ID)
+	case len(args) > 0:
+		for _, feedUrl := range args {
+			updated, urlErr := s.db.RequestFeedRefresh(ctx, feedUrl)
+			if urlErr != nil {
+				err = urlErr
+				break
+			}
+			if updated == 0 {
+				if _, err := s.db.GetFeedByURL(ctx, feedUrl); err != nil {
+					return fmt.Errorf("feed with URL %s not found", feedUrl)
+				}
+				return fmt.Errorf("feed with URL %s is paused or dead, use revive to fetch it again", feedUrl)
+			}
+			requested += updated
+		}
+	default:
+		return fmt.Errorf("enter the refresh command along with a feed url, --all or --following")
+	}
+	if err != nil {
+		return fmt.Errorf("error in queueing the feeds for a refresh: %w", err)
+	}
+
+	if requested == 0 {
+		fmt.Println("No active feeds to refresh")
+		return nil
+	}
+
+	workers, err := s.db.CountLiveAggWorkers(ctx, time.Now().Add(-aggHeartbeatTimeout))
+	if err != nil {
+		return fmt.Errorf("error in checking for running agg workers: %w", err)
+	}
+	if workers > 0 {
+		fmt.Printf("Queued %d feeds, the running agg will fetch them within %s\n", requested, refreshPollInterval)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	summary, err := scrapeUntilIdle(ctx, s, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("========================================")
+	fmt.Println("Feeds refreshed:", summary.Fetched)
+	fmt.Println("New items:", summary.NewItems)
+	fmt.Println("Failures:", summary.Failed)
+	if skipped := int(requested) - summary.Claimed; skipped > 0 {
+		fmt.Printf("%d feeds were skipped because they are paused, dead, rate limited or being fetched by another worker\n", skipped)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+// claimAll leases every due feed to worker and returns how many it got
+func claimAll(t *testing.T, s *state, worker string) int {
+	t.Helper()
+	feeds, err := s.db.ClaimFeedsToFetch(context.Background(), database.ClaimFeedsToFetchParams{
+		Worker:         sql.NullString{String: worker, Valid: true},
+		LeaseExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+		BatchSize:      10,
+	})
+	if err != nil {
+		t.Fatalf("ClaimFeedsToFetch() error = %v", err)
+	}
+	return len(feeds)
+}
+
+func Test_startAggHeartbeat(t *testing.T) {
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "News", "https://news.example/rss")
+	ctx := context.Background()
+
+	stop, err := startAggHeartbeat(ctx, s)
+	if err != nil {
+		t.Fatalf("startAggHeartbeat() error = %v", err)
+	}
+	if live, _ := s.db.CountLiveAggWorkers(ctx, time.Now().Add(-aggHeartbeatTimeout)); live != 1 {
+		t.Errorf("CountLiveAggWorkers() = %d while agg runs, want 1", live)
+	}
+	if claimed := claimAll(t, s, s.workerID); claimed != 1 {
+		t.Fatalf("agg claimed %d feeds, want 1", claimed)
+	}
+	if claimed := claimAll(t, s, "other-worker"); claimed != 0 {
+		t.Errorf("another worker claimed %d leased feeds, want 0", claimed)
+	}
+
+	stop()
+	if live, _ := s.db.CountLiveAggWorkers(ctx, time.Now().Add(-aggHeartbeatTimeout)); live != 0 {
+		t.Errorf("CountLiveAggWorkers() = %d after stop, want 0", live)
+	}
+	if claimed := claimAll(t, s, "other-worker"); claimed != 1 {
+		t.Errorf("another worker claimed %d feeds after stop, want the released lease taken over", claimed)
+	}
+}
+
+func Test_scrapeFeeds_shutdownAborted(t *testing.T) {
+	fetching := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fetching)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "Slow", server.URL+"/feed.xml")
+	s.fetch.shutdownTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-fetching
+		cancel()
+	}()
+	_, err := scrapeFeeds(ctx, s, false)
+	if !errors.Is(err, errShutdownAborted) {
+		t.Fatalf("scrapeFeeds() error = %v, want errShutdownAborted", err)
+	}
+
+	// the aborted feed is neither failed nor left leased to this worker
+	feeds, err := s.db.GetFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	if feeds[0].LastError.Valid || feeds[0].LeasedBy.Valid {
+		t.Errorf("aborted feed = %+v, want no failure recorded and no lease", feeds[0])
+	}
+	if claimed := claimAll(t, s, "other-worker"); claimed != 1 {
+		t.Errorf("another worker claimed %d feeds after the aborted shutdown, want the lease released", claimed)
+	}
+}
+
+func Test_handlerRefresh_inactiveFeeds(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	if err := handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user); err != nil {
+		t.Fatalf("handlerFollow() error = %v", err)
+	}
+	if err := handlerPause(s, command{name: "pause", args: []string{feed.FeedUrl}}); err != nil {
+		t.Fatalf("handlerPause() error = %v", err)
+	}
+	s.config.CurrentUserName = user.UserName
+
+	err := handlerRefresh(s, command{name: "refresh", args: []string{feed.FeedUrl}})
+	if err == nil || !strings.Contains(err.Error(), "paused or dead") {
+		t.Errorf("refresh of a paused feed error = %v, want it rejected", err)
+	}
+	err = handlerRefresh(s, command{name: "refresh", args: []string{"https://missing.example/rss"}})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("refresh of an unknown feed error = %v, want not found", err)
+	}
+	for _, flag := range []string{"--all", "--following"} {
+		output, err := captureOutput(t, func() error {
+			return handlerRefresh(s, command{name: "refresh", args: []string{flag}})
+		})
+		if err != nil || !strings.Contains(output, "No active feeds") {
+			t.Errorf("refresh %s = %q, %v, want the paused feed left out", flag, output, err)
+		}
+	}
+
+	feeds, err := s.db.GetFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	if feeds[0].RefreshRequestedAt.Valid {
+		t.Errorf("paused feed has a refresh queued at %v, want none", feeds[0].RefreshRequestedAt.Time)
+	}
+}
+
+func Test_scrapeFeeds_sharedQueue(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `<rss><channel><title>Example</title></channel></rss>`)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	const feeds = 10
+	for i := 1; i <= feeds; i++ {
+		addTestFeed(t, s, user, fmt.Sprintf("Feed %d", i), fmt.Sprintf("%s/%d.xml", server.URL, i))
+	}
+	ctx := context.Background()
+
+	// a worker that crashed while holding a lease on one feed
+	_, err := s.db.ClaimFeedsToFetch(ctx, database.ClaimFeedsToFetchParams{
+		Worker:         sql.NullString{String: "crashed-worker", Valid: true},
+		LeaseExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Second), Valid: true},
+		BatchSize:      1,
+	})
+	if err != nil {
+		t.Fatalf("ClaimFeedsToFetch() error = %v", err)
+	}
+
+	// two agg processes on the same database, each taking small batches
+	workers := []*state{s, {db: s.db, config: s.config, workerID: "other-worker", ephemeral: true}}
+	var wg sync.WaitGroup
+	summaries := make([]scrapeSummary, len(workers))
+	_, err = captureOutput(t, func() error {
+		errs := make([]error, len(workers))
+		for i, worker := range workers {
+			worker.fetch.batchSize = 2
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				summaries[i], errs[i] = scrapeUntilIdle(ctx, worker, false)
+			}()
+		}
+		wg.Wait()
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		t.Fatalf("scrapeUntilIdle() error = %v", err)
+	}
+
+	if claimed := summaries[0].Claimed + summaries[1].Claimed; claimed != feeds {
+		t.Errorf("workers claimed %d feeds between them, want %d", claimed, feeds)
+	}
+	if len(hits) != feeds {
+		t.Errorf("%d feeds fetched, want all %d including the one with the expired lease", len(hits), feeds)
+	}
+	for path, n := range hits {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, want once", path, n)
+		}
+	}
+}
+
+func Test_scrapeFeeds_staleWorkerAfterTakeover(t *testing.T) {
+	fetching, finish := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fetching)
+		<-finish
+		fmt.Fprint(w, `<rss><channel><title>Slow</title><item><title>Post</title><link>https://slow.example/post</link></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "Slow", server.URL+"/feed.xml")
+	ctx := context.Background()
+
+	var output string
+	done := make(chan error)
+	go func() {
+		var err error
+		output, err = captureOutput(t, func() error {
+			_, err := scrapeFeeds(ctx, s, false)
+			return err
+		})
+		done <- err
+	}()
+
+	// the fetch outlives its lease and another worker takes the feed over
+	<-fetching
+	if err := s.db.ReleaseFeedLeases(ctx, sql.NullString{String: s.workerID, Valid: true}); err != nil {
+		t.Fatalf("ReleaseFeedLeases() error = %v", err)
+	}
+	if claimed := claimAll(t, s, "new-owner"); claimed != 1 {
+		t.Fatalf("new owner claimed %d feeds, want the stale one", claimed)
+	}
+	feeds, _ := s.db.GetFeeds(ctx)
+	lease := feeds[0]
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatalf("scrapeFeeds() error = %v", err)
+	}
+	if !strings.Contains(output, "Dropping the fetch of Slow") {
+		t.Errorf("scrapeFeeds() output = %q, want the stale fetch dropped", output)
+	}
+
+	feeds, err := s.db.GetFeeds(ctx)
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	got := feeds[0]
+	if got.ID != feed.ID || got.LeasedBy != lease.LeasedBy || !got.LeaseExpiresAt.Time.Equal(lease.LeaseExpiresAt.Time) {
+		t.Errorf("lease after the stale worker finished = %v until %v, want the new owner's %v until %v",
+			got.LeasedBy, got.LeaseExpiresAt, lease.LeasedBy, lease.LeaseExpiresAt)
+	}
+	if got.LastFetchedAt.Valid || got.NextFetchAt != lease.NextFetchAt {
+		t.Errorf("stale worker rescheduled the feed: last fetched %v, next %v", got.LastFetchedAt, got.NextFetchAt)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+// agg prunes at most this often, the retention query ranks every stored post
+const autoPruneInterval = time.Hour
+
+// prunePosts removes the posts that fall outside the retention of their feed. It returns the posts
+// it found and how many of them were deleted, a post starred or duplicated in the meantime is kept.
+// With dryRun nothing is removed.
+func prunePosts(ctx context.Context, s *state, dryRun bool) ([]database.GetPrunablePostsRow, int64, error) {
+	posts, err := s.db.GetPrunablePosts(ctx, database.GetPrunablePostsParams{
+		DefaultKeepLast:   int32(s.config.RetentionKeepLast),
+		DefaultMaxAgeDays: int32(s.config.RetentionMaxAgeDays),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error in finding the posts to prune: %w", err)
+	}
+
+	if dryRun || len(posts) == 0 {
+		return posts, 0, nil
+	}
+
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	var deleted int64
+	err = inTx(ctx, s, func(db database.Querier) error {
+		// Items that are still in the feed would otherwise be stored again on the next fetch, see savePosts
+		err := db.RecordPrunedPosts(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("error in recording the pruned posts: %w", err)
+		}
+		deleted, err = db.DeletePosts(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("error in pruning the posts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return posts, deleted, nil
+}
+
+// autoPrune is called by agg after every pass, a failed prune is logged and retried on the next pass
+func autoPrune(ctx context.Context, s *state, lastPrune *time.Time) {
+	if ctx.Err() != nil || time.Since(*lastPrune) < autoPruneInterval {
+		return
+	}
+
+	_, deleted, err := prunePosts(ctx, s, false)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	*lastPrune = time.Now()
+
+	if deleted > 0 {
+		fmt.Printf("Pruned %d posts\n", deleted)
+	}
+}
+
+func handlerPrune(s *state, cmd command) error {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only list the posts that would be removed")
+	_, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the prune flags: %w", err)
+	}
+
+	ctx := context.Background()
+	pruned, deleted, err := prunePosts(ctx, s, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	perFeed := map[string]int{}
+	var feedNames []string
+	for _, post := range pruned {
+		if perFeed[post.FeedName] == 0 {
+			feedNames = append(feedNames, post.FeedName)
+		}
+		perFeed[post.FeedName]++
+		if *dryRun {
+			fmt.Printf("%s: %s\n", post.FeedName, post.Title)
+		}
+	}
+
+	if *dryRun {
+		fmt.Println("========================================")
+	}
+	for _, name := range feedNames {
+		fmt.Printf("%s: %d posts\n", name, perFeed[name])
+	}
+
+	if *dryRun {
+		fmt.Printf("Would prune %d posts\n", len(pruned))
+		return nil
+	}
+
+	fmt.Printf("Pruned %d posts\n", deleted)
+	return nil
+}
+
+// handlerFeedRetention overrides the retention in the config for a single feed, 0 keeps the feed's posts forever
+func handlerFeedRetention(s *state, cmd command) error {
+	fs := flag.NewFlagSet("feedretention", flag.ContinueOnError)
+	keepLast := fs.Int("keep", -1, "keep only the newest number of posts of the feed, 0 for no limit")
+	maxAgeDays := fs.Int("max-age-days", -1, "remove posts of the feed older than this many days, 0 for no limit")
+	clear := fs.Bool("clear", false, "go back to the retention in the config")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the feedretention flags: %w", err)
+	}
+
+	if len(args) < 1 || (!*clear && *keepLast < 0 && *maxAgeDays < 0) {
+		return fmt.Errorf("enter the feedretention command along with the feed url and --keep, --max-age-days or --clear")
+	}
+
+	ctx := context.Background()
+	if *clear {
+		cleared, err := s.db.ClearFeedRetention(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("error in clearing the retention of the feed: %w", err)
+		}
+		if cleared == 0 {
+			return fmt.Errorf("feed with URL %s not found", args[0])
+		}
+		fmt.Println("Feed retention cleared")
+		return nil
+	}
+
+	// A limit that isn't given stays NULL, which keeps the feed's current override
+	params := database.SetFeedRetentionParams{FeedUrl: args[0]}
+	if *keepLast >= 0 {
+		params.RetentionKeepLast = sql.NullInt32{Int32: int32(*keepLast), Valid: true}
+	}
+	if *maxAgeDays >= 0 {
+		params.RetentionMaxAgeDays = sql.NullInt32{Int32: int32(*maxAgeDays), Valid: true}
+	}
+
+	updated, err := s.db.SetFeedRetention(ctx, params)
+	if err != nil {
+		return fmt.Errorf("error in saving the retention of the feed: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("feed with URL %s not found", params.FeedUrl)
+	}
+
+	fmt.Println("Feed retention updated")
+	if params.RetentionKeepLast.Valid {
+		fmt.Println("Keep last:", params.RetentionKeepLast.Int32)
+	}
+	if params.RetentionMaxAgeDays.Valid {
+		fmt.Println("Max age in days:", params.RetentionMaxAgeDays.Int32)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+// testItems returns n items published an hour apart, the first one is the newest
+func testItems(n int) []RSSItem {
+	items := make([]RSSItem, n)
+	for i := range items {
+		items[i] = RSSItem{
+			Title:       fmt.Sprintf("Story %d", i+1),
+			Link:        fmt.Sprintf("https://news.example/story-%d", i+1),
+			Description: fmt.Sprintf("Body of story %d", i+1),
+			GUID:        fmt.Sprintf("story-%d", i+1),
+			PubDate:     time.Now().Add(-time.Duration(i) * time.Hour).Format(time.RFC1123Z),
+		}
+	}
+	return items
+}
+
+func Test_prunePosts_notStoredAgain(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	ctx := context.Background()
+
+	items := testItems(3)
+	if _, err := savePosts(ctx, s, feed.ID, items); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+	if err := handlerFeedRetention(s, command{name: "feedretention", args: []string{feed.FeedUrl, "--keep", "1"}}); err != nil {
+		t.Fatalf("handlerFeedRetention() error = %v", err)
+	}
+
+	_, deleted, err := prunePosts(ctx, s, false)
+	if err != nil || deleted != 2 {
+		t.Fatalf("prunePosts() = %d, %v, want 2 deleted", deleted, err)
+	}
+
+	// The feed still lists every item, the pruned ones must not come back as new
+	newItems, err := savePosts(ctx, s, feed.ID, items)
+	if err != nil || newItems != 0 {
+		t.Errorf("savePosts() after pruning = %d, %v, want no new items", newItems, err)
+	}
+	if pruned, _, _ := prunePosts(ctx, s, true); len(pruned) != 0 {
+		t.Errorf("prunePosts() found %d posts to prune again, want none", len(pruned))
+	}
+
+	// A new item is still stored
+	fresh := RSSItem{Title: "Fresh", Link: "https://news.example/fresh", Description: "New story", PubDate: time.Now().Add(time.Minute).Format(time.RFC1123Z)}
+	if newItems, _ := savePosts(ctx, s, feed.ID, append(items, fresh)); newItems != 1 {
+		t.Errorf("savePosts() of a new item = %d, want 1", newItems)
+	}
+}
+
+func Test_handlerFeedRetention(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+
+	run := func(args ...string) {
+		t.Helper()
+		if err := handlerFeedRetention(s, command{name: "feedretention", args: append([]string{feed.FeedUrl}, args...)}); err != nil {
+			t.Fatalf("handlerFeedRetention(%v) error = %v", args, err)
+		}
+	}
+	current := func() (keep, maxAge int32, keepSet, maxAgeSet bool) {
+		t.Helper()
+		feeds, err := s.db.GetFeeds(context.Background())
+		if err != nil || len(feeds) != 1 {
+			t.Fatalf("GetFeeds() = %v, %v", feeds, err)
+		}
+		f := feeds[0]
+		return f.RetentionKeepLast.Int32, f.RetentionMaxAgeDays.Int32, f.RetentionKeepLast.Valid, f.RetentionMaxAgeDays.Valid
+	}
+
+	run("--max-age-days", "90")
+	run("--keep", "200")
+	if keep, maxAge, _, _ := current(); keep != 200 || maxAge != 90 {
+		t.Errorf("after --keep, retention = %d, %d, want the max age kept", keep, maxAge)
+	}
+
+	run("--max-age-days", "30")
+	if keep, maxAge, _, _ := current(); keep != 200 || maxAge != 30 {
+		t.Errorf("after --max-age-days, retention = %d, %d, want keep last kept", keep, maxAge)
+	}
+
+	run("--clear")
+	if _, _, keepSet, maxAgeSet := current(); keepSet || maxAgeSet {
+		t.Errorf("after --clear, the retention is still set")
+	}
+}
+
+func Test_handlerPrune(t *testing.T) {
+	newState := func(t *testing.T) (*state, uuid.UUID, []uuid.UUID) {
+		s, user := newTestState(t)
+		feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+		ctx := context.Background()
+		if _, err := savePosts(ctx, s, feed.ID, testItems(4)); err != nil {
+			t.Fatalf("savePosts() error = %v", err)
+		}
+		if err := handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user); err != nil {
+			t.Fatalf("handlerFollow() error = %v", err)
+		}
+
+		// Newest first
+		posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+		if err != nil || len(posts) != 4 {
+			t.Fatalf("GetPostsForUser() = %d posts, %v, want 4", len(posts), err)
+		}
+		ids := make([]uuid.UUID, len(posts))
+		for i, post := range posts {
+			ids[i] = post.ID
+		}
+		return s, user.ID, ids
+	}
+	remaining := func(t *testing.T, s *state, userID uuid.UUID) []string {
+		posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{UserID: userID, IncludeRead: true, PostLimit: 10})
+		if err != nil {
+			t.Fatalf("GetPostsForUser() error = %v", err)
+		}
+		var titles []string
+		for _, post := range posts {
+			titles = append(titles, post.Title)
+		}
+		return titles
+	}
+
+	t.Run("keep last", func(t *testing.T) {
+		s, userID, _ := newState(t)
+		s.config.RetentionKeepLast = 2
+		if err := handlerPrune(s, command{name: "prune"}); err != nil {
+			t.Fatalf("handlerPrune() error = %v", err)
+		}
+		if got := remaining(t, s, userID); !slices.Equal(got, []string{"Story 1", "Story 2"}) {
+			t.Errorf("remaining posts = %v, want the two newest", got)
+		}
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		s, userID, _ := newState(t)
+		// Stories are an hour apart, only the ones older than a day go
+		items := testItems(1)
+		items[0].Title, items[0].Link, items[0].GUID = "Old story", "https://news.example/old", "old"
+		items[0].PubDate = time.Now().AddDate(0, 0, -3).Format(time.RFC1123Z)
+		feeds, _ := s.db.GetFeeds(context.Background())
+		if _, err := savePosts(context.Background(), s, feeds[0].ID, items); err != nil {
+			t.Fatalf("savePosts() error = %v", err)
+		}
+
+		s.config.RetentionMaxAgeDays = 1
+		if err := handlerPrune(s, command{name: "prune"}); err != nil {
+			t.Fatalf("handlerPrune() error = %v", err)
+		}
+		if got := remaining(t, s, userID); len(got) != 4 || slices.Contains(got, "Old story") {
+			t.Errorf("remaining posts = %v, want the four recent stories", got)
+		}
+	})
+
+	t.Run("starred posts are kept", func(t *testing.T) {
+		s, userID, ids := newState(t)
+		user, _ := s.db.GetUser(context.Background(), "alice")
+		if err := handlerStar(s, command{name: "star", args: []string{ids[3].String()}}, user); err != nil {
+			t.Fatalf("handlerStar() error = %v", err)
+		}
+
+		s.config.RetentionKeepLast = 1
+		if err := handlerPrune(s, command{name: "prune"}); err != nil {
+			t.Fatalf("handlerPrune() error = %v", err)
+		}
+		if got := remaining(t, s, userID); !slices.Equal(got, []string{"Story 1", "Story 4"}) {
+			t.Errorf("remaining posts = %v, want the newest and the starred one", got)
+		}
+	})
+
+	t.Run("dry run deletes nothing", func(t *testing.T) {
+		s, userID, _ := newState(t)
+		s.config.RetentionKeepLast = 1
+		if err := handlerPrune(s, command{name: "prune", args: []string{"--dry-run"}}); err != nil {
+			t.Fatalf("handlerPrune() error = %v", err)
+		}
+		if got := remaining(t, s, userID); len(got) != 4 {
+			t.Errorf("remaining posts = %v, want all four", got)
+		}
+		if pruned, _, _ := prunePosts(context.Background(), s, true); len(pruned) != 3 {
+			t.Errorf("prunePosts() dry run found %d posts, want 3 still prunable", len(pruned))
+		}
+	})
+}
+
+func Test_prunePosts_canonicalWithDuplicates(t *testing.T) {
+	backends := map[string]func(t *testing.T) (*state, database.User){
+		"memory": newTestState,
+		"sqlite": newSQLiteTestState,
+	}
+	for name, newState := range backends {
+		t.Run(name, func(t *testing.T) {
+			s, user := newState(t)
+			ctx := context.Background()
+
+			// The same old story in two feeds, the first one stored is the canonical post
+			story := RSSItem{
+				Title:       "Shared story",
+				Link:        "https://news.example/shared",
+				Description: "Told by both feeds",
+				PubDate:     time.Now().AddDate(0, 0, -3).Format(time.RFC1123Z),
+			}
+			for _, feedURL := range []string{"https://first.example/rss", "https://second.example/rss"} {
+				feed := addTestFeed(t, s, user, feedURL, feedURL)
+				if _, err := captureOutput(t, func() error {
+					return handlerFollow(s, command{name: "follow", args: []string{feedURL}}, user)
+				}); err != nil {
+					t.Fatalf("handlerFollow() error = %v", err)
+				}
+				if _, err := savePosts(ctx, s, feed.ID, []RSSItem{story}); err != nil {
+					t.Fatalf("savePosts() error = %v", err)
+				}
+			}
+
+			posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+			if err != nil || len(posts) != 1 {
+				t.Fatalf("GetPostsForUser() = %d posts, %v, want the story once", len(posts), err)
+			}
+			if _, err := captureOutput(t, func() error {
+				return handlerRead(s, command{name: "read", args: []string{posts[0].ID.String()}}, user)
+			}); err != nil {
+				t.Fatalf("handlerRead() error = %v", err)
+			}
+
+			// Both copies are too old, the canonical one waits until its duplicate is gone
+			s.config.RetentionMaxAgeDays = 1
+			for pass, want := range []int64{1, 1, 0} {
+				_, deleted, err := prunePosts(ctx, s, false)
+				if err != nil || deleted != want {
+					t.Fatalf("prunePosts() pass %d = %d, %v, want %d deleted", pass+1, deleted, err, want)
+				}
+
+				all, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, IncludeRead: true, PostLimit: 10})
+				if err != nil {
+					t.Fatalf("GetPostsForUser() error = %v", err)
+				}
+				if pass == 0 && (len(all) != 1 || !all[0].IsRead) {
+					t.Errorf("posts after pruning the duplicate = %+v, want the story once and still read", all)
+				}
+				if pass > 0 && len(all) != 0 {
+					t.Errorf("posts after pruning the canonical post = %+v, want none", all)
+				}
+			}
+		})
+	}
+}
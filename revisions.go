@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/dedup"
+	"github.com/Pradhyumna789/RSS/internal/diff"
+	"github.com/google/uuid"
+)
+
+// Words of unchanged text shown on each side of an edit by history, unless --full is given
+const historyContext = 8
+
+// reviseKnownPost looks the item up by its guid and reports whether the feed already has it.
+// When the title, description or content changed, the stored version is kept in post_revisions
+// and the post is updated to the new one.
+func reviseKnownPost(ctx context.Context, s *state, feedID uuid.UUID, item RSSItem) (bool, error) {
+	if item.GUID == "" {
+		return false, nil
+	}
+
+	post, err := s.db.GetPostByGUID(ctx, database.GetPostByGUIDParams{
+		FeedID: feedID,
+		Guid:   sql.NullString{String: item.GUID, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error in looking up the post with guid %s: %w", item.GUID, err)
+	}
+
+	if sameText(post.Title, item.Title) && sameText(post.Description.String, item.Description) && sameText(post.Content.String, item.Content) {
+		return true, nil
+	}
+
+	err = s.db.CreatePostRevision(ctx, database.CreatePostRevisionParams{
+		ID:          uuid.New(),
+		PostID:      post.ID,
+		RevisedAt:   time.Now(),
+		Title:       post.Title,
+		Description: post.Description,
+		Content:     post.Content,
+	})
+	if err != nil {
+		return true, fmt.Errorf("error in saving the previous version of the post %s: %w", item.Link, err)
+	}
+
+	err = s.db.UpdatePostContent(ctx, database.UpdatePostContentParams{
+		ID:          post.ID,
+		Title:       item.Title,
+		Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
+		Content:     sql.NullString{String: item.Content, Valid: item.Content != ""},
+		Fingerprint: dedup.Fingerprint(item.Title, item.Description, item.Content),
+	})
+	if err != nil {
+		return true, fmt.Errorf("error in updating the post %s: %w", item.Link, err)
+	}
+
+	fmt.Println("Post edited by the publisher:", item.Title)
+	return true, nil
+}
+
+// sameText ignores edits that only reflow whitespace
+func sameText(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+// postVersion is what a post looked like from since until the next version replaced it
+type postVersion struct {
+	since       time.Time
+	title       string
+	description string
+	content     string
+}
+
+// handlerHistory lists every stored version of a post with a word diff against the version before it
+func handlerHistory(s *state, cmd command) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	full := fs.Bool("full", false, "show the whole text of every changed field instead of the words around each edit")
+	args, err := parseFlags(fs, cmd.args)
+	if err != nil {
+		return fmt.Errorf("error in parsing the history flags: %w", err)
+	}
+
+	postID, err := parsePostID(command{name: cmd.name, args: args})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	post, err := s.db.GetPost(ctx, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("post %s doesn't exist", postID)
+	}
+	if err != nil {
+		return fmt.Errorf("error in fetching the post: %w", err)
+	}
+
+	revisions, err := s.db.GetPostRevisions(ctx, postID)
+	if err != nil {
+		return fmt.Errorf("error in fetching the revisions of the post: %w", err)
+	}
+
+	fmt.Println("Post ID:", post.ID)
+	fmt.Println("Title:", post.Title)
+	fmt.Println("Link:", post.Url)
+	fmt.Println("Feed:", post.FeedName)
+
+	if len(revisions) == 0 {
+		fmt.Println("No edits recorded, the post is unchanged since", post.CreatedAt)
+		return nil
+	}
+
+	// Each revision holds the version that was replaced at revised_at, the post holds the current one
+	var versions []postVersion
+	since := post.CreatedAt
+	for _, revision := range revisions {
+		versions = append(versions, postVersion{
+			since:       since,
+			title:       revision.Title,
+			description: revision.Description.String,
+			content:     revision.Content.String,
+		})
+		since = revision.RevisedAt
+	}
+	versions = append(versions, postVersion{
+		since:       since,
+		title:       post.Title,
+		description: post.Description.String,
+		content:     post.Content.String,
+	})
+
+	contextWords := historyContext
+	if *full {
+		contextWords = -1
+	}
+
+	fmt.Println("Versions:", len(versions))
+	fmt.Println("----------------------------------------")
+	fmt.Println("Version 1, first seen", versions[0].since)
+	fmt.Println("----------------------------------------")
+	for i := 1; i < len(versions); i++ {
+		previous, current := versions[i-1], versions[i]
+		fmt.Printf("Version %d, edited %s\n", i+1, current.since)
+		printFieldDiff("Title", previous.title, current.title, contextWords)
+		printFieldDiff("Description", previous.description, current.description, contextWords)
+		printFieldDiff("Content", previous.content, current.content, contextWords)
+		fmt.Println("----------------------------------------")
+	}
+
+	return nil
+}
+
+// printFieldDiff prints a word diff of one field, or nothing if the field didn't change
+func printFieldDiff(name, old, new string, contextWords int) {
+	chunks := diff.Words(old, new)
+	if !diff.Changed(chunks) {
+		return
+	}
+	fmt.Printf("%s: %s\n", name, diff.Format(chunks, contextWords))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+func Test_savePosts_revisions(t *testing.T) {
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	if err := handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user); err != nil {
+		t.Fatalf("handlerFollow() error = %v", err)
+	}
+	ctx := context.Background()
+
+	item := RSSItem{
+		Title:       "Mayor resigns",
+		Link:        "https://news.example/mayor",
+		Description: "The mayor resigned on Monday.",
+		GUID:        "news-1",
+	}
+	newItems, err := savePosts(ctx, s, feed.ID, []RSSItem{item})
+	if err != nil || newItems != 1 {
+		t.Fatalf("savePosts() = %d, %v, want 1 new item", newItems, err)
+	}
+
+	// Reflowed whitespace is not an edit
+	item.Description = "The mayor resigned\n on Monday."
+	if _, err := savePosts(ctx, s, feed.ID, []RSSItem{item}); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+
+	// A correction under a new link is still the same post
+	item.Title = "Deputy mayor resigns"
+	item.Link = "https://news.example/deputy-mayor"
+	newItems, err = savePosts(ctx, s, feed.ID, []RSSItem{item})
+	if err != nil || newItems != 0 {
+		t.Fatalf("savePosts() of an edited item = %d, %v, want no new items", newItems, err)
+	}
+
+	posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForUser() error = %v", err)
+	}
+	if len(posts) != 1 || posts[0].Title != "Deputy mayor resigns" {
+		t.Fatalf("posts = %+v, want the single edited post", posts)
+	}
+
+	revisions, err := s.db.GetPostRevisions(ctx, posts[0].ID)
+	if err != nil {
+		t.Fatalf("GetPostRevisions() error = %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Title != "Mayor resigns" {
+		t.Errorf("GetPostRevisions() = %+v, want the original title kept once", revisions)
+	}
+}
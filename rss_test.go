@@ -0,0 +1,679 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pradhyumna789/RSS/internal/config"
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/Pradhyumna789/RSS/internal/database/memory"
+	"github.com/Pradhyumna789/RSS/internal/schedule"
+	"github.com/google/uuid"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 0, 0, 0, time.UTC)
+	type args struct {
+		value string
+		now   time.Time
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   time.Time
+		wantOk bool
+	}{
+		{"seconds", args{"120", now}, now.Add(2 * time.Minute), true},
+		{"http date", args{"Wed, 21 Oct 2015 07:28:00 GMT", now}, time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC), true},
+		{"empty", args{"", now}, time.Time{}, false},
+		{"negative", args{"-5", now}, time.Time{}, false},
+		{"garbage", args{"soon", now}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := parseRetryAfter(tt.args.value, tt.args.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("parseRetryAfter() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("parseRetryAfter() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parsePubDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Time
+		wantOk bool
+	}{
+		{"rfc1123z", "Wed, 21 Oct 2015 07:28:00 +0000", time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC), true},
+		{"single digit day", "Fri, 2 Oct 2015 07:28:00 +0000", time.Date(2015, time.October, 2, 7, 28, 0, 0, time.UTC), true},
+		{"rfc3339", "2015-10-21T07:28:00Z", time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC), true},
+		{"empty", "", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := parsePubDate(tt.value)
+			if !got.Equal(tt.want) {
+				t.Errorf("parsePubDate() got = %v, want %v", got, tt.want)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("parsePubDate() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_feedHints(t *testing.T) {
+	data := `<rss xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"><channel>
+		<ttl>60</ttl>
+		<skipHours><hour>1</hour><hour>2</hour></skipHours>
+		<skipDays><day>Sunday</day></skipDays>
+		<sy:updatePeriod>daily</sy:updatePeriod>
+		<sy:updateFrequency>2</sy:updateFrequency>
+	</channel></rss>`
+
+	var rssFeed RSSFeed
+	if err := xml.Unmarshal([]byte(data), &rssFeed); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+
+	hints := feedHints(&rssFeed)
+	want := schedule.Hints{
+		TTL:             time.Hour,
+		SkipHours:       []int{1, 2},
+		SkipDays:        []time.Weekday{time.Sunday},
+		UpdatePeriod:    "daily",
+		UpdateFrequency: 2,
+	}
+	if !reflect.DeepEqual(hints, want) {
+		t.Errorf("feedHints() = %+v, want %+v", hints, want)
+	}
+}
+
+// newTestState returns a state backed by an in-memory store with one registered user
+func newTestState(t *testing.T) (*state, database.User) {
+	t.Helper()
+	s := &state{db: memory.NewStore(), config: &config.Config{}, workerID: "test-worker", ephemeral: true}
+	user, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserName:  "alice",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	return s, user
+}
+
+func addTestFeed(t *testing.T, s *state, user database.User, name, feedURL string) database.Feed {
+	t.Helper()
+	feed, err := s.db.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:        uuid.New(),
+		Createdat: time.Now(),
+		Updatedat: time.Now(),
+		FeedName:  name,
+		FeedUrl:   feedURL,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed() error = %v", err)
+	}
+	return feed
+}
+
+// captureOutput runs fn and returns what it printed
+func captureOutput(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+	fnErr := fn()
+	w.Close()
+	return <-output, fnErr
+}
+
+func Test_parseFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+		feed string
+	}{
+		{"flags after the words", []string{"climate", "--feed", "x"}, []string{"climate"}, "x"},
+		{"flags between the words", []string{"climate", "--feed", "x", "change"}, []string{"climate", "change"}, "x"},
+		{"dash words after --", []string{"--feed", "x", "--", "climate", "-sports"}, []string{"climate", "-sports"}, "x"},
+		{"-- after a word", []string{"climate", "--", "--feed", "x"}, []string{"climate", "--feed", "x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			feed := fs.String("feed", "", "")
+			got, err := parseFlags(fs, tt.args)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || *feed != tt.feed {
+				t.Errorf("parseFlags() = %q, --feed %q, want %q, --feed %q", got, *feed, tt.want, tt.feed)
+			}
+		})
+	}
+}
+
+func Test_handlerFollow(t *testing.T) {
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "Example", "https://example.com/rss")
+
+	if err := handlerFollow(s, command{name: "follow"}, user); err == nil {
+		t.Errorf("handlerFollow() without a url, want an error")
+	}
+	if err := handlerFollow(s, command{name: "follow", args: []string{"https://example.com/missing"}}, user); err == nil {
+		t.Errorf("handlerFollow() of an unknown feed, want an error")
+	}
+	if err := handlerFollow(s, command{name: "follow", args: []string{"https://example.com/rss"}}, user); err != nil {
+		t.Fatalf("handlerFollow() error = %v", err)
+	}
+
+	follows, err := s.db.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser() error = %v", err)
+	}
+	want := []database.GetFeedFollowsForUserRow{{UserName: "alice", FeedName: "Example"}}
+	if !reflect.DeepEqual(follows, want) {
+		t.Errorf("GetFeedFollowsForUser() = %+v, want %+v", follows, want)
+	}
+}
+
+func Test_scrapeFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<rss><channel><title>Example</title>
+			<item><title>First</title><link>%[1]s/first</link><pubDate>Mon, 01 Jan 2024 10:00:00 +0000</pubDate></item>
+			<item><title>Second</title><link>%[1]s/second</link><pubDate>Tue, 02 Jan 2024 10:00:00 +0000</pubDate></item>
+		</channel></rss>`, "http://"+r.Host)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "Example", server.URL+"/feed.xml")
+	addTestFeed(t, s, user, "Broken", server.URL+"/missing.xml")
+	if err := handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user); err != nil {
+		t.Fatalf("handlerFollow() error = %v", err)
+	}
+
+	ctx := context.Background()
+	summary, err := scrapeFeeds(ctx, s, false)
+	if err != nil {
+		t.Fatalf("scrapeFeeds() error = %v", err)
+	}
+	want := scrapeSummary{Claimed: 2, Fetched: 1, Failed: 1, NewItems: 2}
+	if summary != want {
+		t.Errorf("scrapeFeeds() = %+v, want %+v", summary, want)
+	}
+
+	posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForUser() error = %v", err)
+	}
+	var titles []string
+	for _, post := range posts {
+		titles = append(titles, post.Title)
+	}
+	if !reflect.DeepEqual(titles, []string{"Second", "First"}) {
+		t.Errorf("posts after scrapeFeeds() = %v, want [Second First]", titles)
+	}
+
+	// Both feeds were rescheduled, so a second tick right away has nothing to do
+	summary, err = scrapeFeeds(ctx, s, false)
+	if err != nil {
+		t.Fatalf("second scrapeFeeds() error = %v", err)
+	}
+	if summary != (scrapeSummary{}) {
+		t.Errorf("second scrapeFeeds() = %+v, want nothing claimed", summary)
+	}
+}
+
+func Test_checkReplay(t *testing.T) {
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "News", "https://news.example/rss")
+	s.ephemeral = false
+
+	commands := []struct {
+		handler func(*state, command) error
+		cmd     command
+	}{
+		{handlerAgg, command{name: "agg", args: []string{"--once", "--replay", t.TempDir()}}},
+		{handlerRefresh, command{name: "refresh", args: []string{"--replay", t.TempDir(), "https://news.example/rss"}}},
+	}
+	for _, c := range commands {
+		err := c.handler(s, c.cmd)
+		if err == nil || !strings.Contains(err.Error(), "--ephemeral") {
+			t.Errorf("%s %v error = %v, want --replay refused outside --ephemeral", c.cmd.name, c.cmd.args, err)
+		}
+	}
+
+	feeds, err := s.db.GetFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	if feeds[0].LastFetchedAt.Valid || feeds[0].LastError.Valid {
+		t.Errorf("feed = %+v, want it untouched by the refused replay", feeds[0])
+	}
+
+	s.ephemeral = true
+	s.replayDir = ""
+	if err := checkReplay(s, "agg"); err != nil {
+		t.Errorf("checkReplay() without --replay error = %v", err)
+	}
+	s.replayDir = t.TempDir()
+	if err := checkReplay(s, "agg"); err != nil {
+		t.Errorf("checkReplay() in an --ephemeral session error = %v", err)
+	}
+}
+
+func Test_handlerAgg_onceQuietHours(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss><channel><title>Example</title><item><title>First</title><link>https://example.com/first</link></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	inside := now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")
+	outside := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "Example", server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return handlerAgg(s, command{name: "agg", args: []string{"--once", "--quiet-hours", inside}})
+	})
+	if err != nil || !strings.Contains(output, "quiet hours") {
+		t.Fatalf("agg --once inside the quiet hours = %q, %v, want nothing fetched", output, err)
+	}
+	feeds, _ := s.db.GetFeeds(context.Background())
+	if feeds[0].LastFetchedAt.Valid {
+		t.Errorf("agg --once fetched the feed inside the quiet hours")
+	}
+
+	if _, err := captureOutput(t, func() error {
+		return handlerAgg(s, command{name: "agg", args: []string{"--once", "--quiet-hours", outside}})
+	}); err != nil {
+		t.Fatalf("agg --once outside the quiet hours error = %v", err)
+	}
+	feeds, _ = s.db.GetFeeds(context.Background())
+	if !feeds[0].LastFetchedAt.Valid {
+		t.Errorf("agg --once didn't fetch the feed outside the quiet hours")
+	}
+}
+
+func Test_scrapeFeeds_intervalFromStoredHistory(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	// the feed only ever lists its latest post
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<rss><channel><title>Example</title>
+			<item><title>Latest</title><link>https://example.com/latest</link><pubDate>%s</pubDate></item>
+		</channel></rss>`, now.Format(time.RFC1123Z))
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	feed := addTestFeed(t, s, user, "Example", server.URL)
+
+	// posts stored by earlier fetches, four hours apart
+	var earlier []RSSItem
+	for i := 1; i <= 3; i++ {
+		earlier = append(earlier, RSSItem{
+			Title:   fmt.Sprintf("Earlier %d", i),
+			Link:    fmt.Sprintf("https://example.com/earlier-%d", i),
+			PubDate: now.Add(-time.Duration(4*i) * time.Hour).Format(time.RFC1123Z),
+		})
+	}
+	if _, err := savePosts(context.Background(), s, feed.ID, earlier); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+
+	if _, err := captureOutput(t, func() error {
+		_, err := scrapeFeeds(context.Background(), s, false)
+		return err
+	}); err != nil {
+		t.Fatalf("scrapeFeeds() error = %v", err)
+	}
+
+	feeds, err := s.db.GetFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	got := time.Duration(feeds[0].FetchIntervalSeconds.Int32) * time.Second
+	if want := 2 * time.Hour; got != want {
+		t.Errorf("fetch interval = %s, want %s, half the four hour gap between the stored posts", got, want)
+	}
+}
+
+func Test_deadFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone.xml" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	gone := addTestFeed(t, s, user, "Gone", server.URL+"/gone.xml")
+	missing := addTestFeed(t, s, user, "Missing", server.URL+"/missing.xml")
+	ctx := context.Background()
+
+	if _, err := captureOutput(t, func() error {
+		_, err := scrapeFeeds(ctx, s, false)
+		return err
+	}); err != nil {
+		t.Fatalf("scrapeFeeds() error = %v", err)
+	}
+	feedStatus := func(id uuid.UUID) database.Feed {
+		t.Helper()
+		feeds, err := s.db.GetFeeds(ctx)
+		if err != nil {
+			t.Fatalf("GetFeeds() error = %v", err)
+		}
+		for _, feed := range feeds {
+			if feed.ID == id {
+				return feed
+			}
+		}
+		t.Fatalf("feed %s not found", id)
+		return database.Feed{}
+	}
+	if feed := feedStatus(gone.ID); feed.Status != feedStatusDead {
+		t.Errorf("feed answering 410 has status %q, want %q", feed.Status, feedStatusDead)
+	}
+	if feed := feedStatus(missing.ID); feed.Status != feedStatusActive || feed.ConsecutiveNotFound != 1 {
+		t.Errorf("feed after one 404 = status %q, %d not found, want active and 1", feed.Status, feed.ConsecutiveNotFound)
+	}
+
+	notFound := &fetchStatusError{URL: missing.FeedUrl, StatusCode: http.StatusNotFound}
+	for i := 2; i <= maxConsecutiveNotFound; i++ {
+		if _, err := captureOutput(t, func() error {
+			return recordFetchFailure(ctx, s, missing.ID, notFound)
+		}); err != nil {
+			t.Fatalf("recordFetchFailure() error = %v", err)
+		}
+		want := feedStatusActive
+		if i == maxConsecutiveNotFound {
+			want = feedStatusDead
+		}
+		if feed := feedStatus(missing.ID); feed.Status != want {
+			t.Errorf("feed after %d 404s has status %q, want %q", i, feed.Status, want)
+		}
+	}
+	if claimed := claimAll(t, s, s.workerID); claimed != 0 {
+		t.Errorf("agg claimed %d dead feeds, want 0", claimed)
+	}
+
+	output, err := captureOutput(t, func() error {
+		return handlerDeadFeeds(s, command{name: "deadfeeds"})
+	})
+	if err != nil || !strings.Contains(output, "410 Gone") || !strings.Contains(output, "404 Not Found 5 times") {
+		t.Errorf("deadfeeds = %q, %v, want both feeds with their reasons", output, err)
+	}
+
+	s.config.CurrentUserName = user.UserName
+	refresh := command{name: "refresh", args: []string{missing.FeedUrl}}
+	if err := handlerRefresh(s, refresh); err == nil {
+		t.Errorf("refresh of a dead feed, want an error")
+	}
+
+	if _, err := captureOutput(t, func() error {
+		return handlerRevive(s, command{name: "revive", args: []string{missing.FeedUrl}})
+	}); err != nil {
+		t.Fatalf("handlerRevive() error = %v", err)
+	}
+	if feed := feedStatus(missing.ID); feed.Status != feedStatusActive || feed.ConsecutiveNotFound != 0 {
+		t.Errorf("revived feed = status %q, %d not found, want active and the count reset", feed.Status, feed.ConsecutiveNotFound)
+	}
+	if _, err := captureOutput(t, func() error { return handlerRefresh(s, refresh) }); err != nil {
+		t.Fatalf("refresh of the revived feed error = %v", err)
+	}
+	// without a running agg, refresh fetched the feed itself and it answered 404 again
+	if feed := feedStatus(missing.ID); feed.Status != feedStatusActive || feed.ConsecutiveNotFound != 1 {
+		t.Errorf("revived feed after a refresh = status %q, %d not found, want active and 1", feed.Status, feed.ConsecutiveNotFound)
+	}
+}
+
+func Test_scrapeFeeds_workerPool(t *testing.T) {
+	const fast = 3
+	var inFlight atomic.Int32
+	allInFlight := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.xml" {
+			<-r.Context().Done()
+			return
+		}
+		// each fast feed answers only once all of them are being fetched at the same time
+		if inFlight.Add(1) == fast {
+			close(allInFlight)
+		}
+		select {
+		case <-allInFlight:
+		case <-time.After(5 * time.Second):
+			http.Error(w, "fetched one after another", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `<rss><channel><title>Fast</title><item><title>Post</title><link>https://example.com%s</link></item></channel></rss>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	addTestFeed(t, s, user, "Slow", server.URL+"/slow.xml")
+	for i := 1; i <= fast; i++ {
+		addTestFeed(t, s, user, fmt.Sprintf("Fast %d", i), fmt.Sprintf("%s/fast-%d.xml", server.URL, i))
+	}
+	s.fetch.concurrency = fast + 1
+	s.fetch.timeout = 200 * time.Millisecond
+
+	output, err := captureOutput(t, func() error {
+		summary, err := scrapeFeeds(context.Background(), s, false)
+		if want := (scrapeSummary{Claimed: fast + 1, Fetched: fast, Failed: 1, NewItems: fast}); summary != want {
+			t.Errorf("scrapeFeeds() = %+v, want %+v", summary, want)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("scrapeFeeds() error = %v", err)
+	}
+	if !strings.Contains(output, "deadline exceeded") {
+		t.Errorf("scrapeFeeds() output = %q, want the slow feed cut off by --timeout", output)
+	}
+}
+
+func Test_scrapeFeeds_batch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss><channel><title>Example</title></channel></rss>`)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	for i := 1; i <= 5; i++ {
+		addTestFeed(t, s, user, fmt.Sprintf("Feed %d", i), fmt.Sprintf("%s/%d.xml", server.URL, i))
+	}
+	s.fetch.batchSize = 2
+
+	for _, want := range []int{2, 2, 1, 0} {
+		var summary scrapeSummary
+		if _, err := captureOutput(t, func() error {
+			var err error
+			summary, err = scrapeFeeds(context.Background(), s, false)
+			return err
+		}); err != nil {
+			t.Fatalf("scrapeFeeds() error = %v", err)
+		}
+		if summary.Claimed != want {
+			t.Errorf("scrapeFeeds() claimed %d feeds with --batch 2, want %d", summary.Claimed, want)
+		}
+	}
+}
+
+func Test_scrapeFeeds_mostOverdueFirst(t *testing.T) {
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = append(fetched, r.URL.Path)
+		fmt.Fprint(w, `<rss><channel><title>Example</title></channel></rss>`)
+	}))
+	defer server.Close()
+
+	s, user := newTestState(t)
+	ctx := context.Background()
+	overdue := map[string]time.Duration{"/day.xml": 24 * time.Hour, "/minute.xml": time.Minute, "/hour.xml": time.Hour}
+	ids := map[string]uuid.UUID{}
+	for path := range overdue {
+		ids[path] = addTestFeed(t, s, user, path, server.URL+path).ID
+	}
+	// only the lease holder can reschedule a feed
+	claimAll(t, s, "setup")
+	for path, by := range overdue {
+		marked, err := s.db.MarkFeedFetched(ctx, database.MarkFeedFetchedParams{
+			ID:          ids[path],
+			NextFetchAt: sql.NullTime{Time: time.Now().Add(-by), Valid: true},
+			LeasedBy:    sql.NullString{String: "setup", Valid: true},
+		})
+		if err != nil || marked != 1 {
+			t.Fatalf("MarkFeedFetched() = %d, %v", marked, err)
+		}
+	}
+	s.fetch.batchSize = 1
+
+	lastFetched := map[string]time.Time{}
+	feeds, err := s.db.GetFeeds(ctx)
+	if err != nil {
+		t.Fatalf("GetFeeds() error = %v", err)
+	}
+	for _, feed := range feeds {
+		lastFetched[feed.FeedName] = feed.LastFetchedAt.Time
+	}
+	for range overdue {
+		if _, err := captureOutput(t, func() error {
+			_, err := scrapeFeeds(ctx, s, false)
+			return err
+		}); err != nil {
+			t.Fatalf("scrapeFeeds() error = %v", err)
+		}
+
+		// only the feed fetched on this tick is marked
+		feeds, err := s.db.GetFeeds(ctx)
+		if err != nil {
+			t.Fatalf("GetFeeds() error = %v", err)
+		}
+		for _, feed := range feeds {
+			if feed.LastFetchedAt.Time != lastFetched[feed.FeedName] && feed.FeedName != fetched[len(fetched)-1] {
+				t.Errorf("feed %s was marked fetched while %s was fetched", feed.FeedName, fetched[len(fetched)-1])
+			}
+			lastFetched[feed.FeedName] = feed.LastFetchedAt.Time
+		}
+	}
+
+	want := []string{"/day.xml", "/hour.xml", "/minute.xml"}
+	if !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetch order = %v, want the most overdue feed first %v", fetched, want)
+	}
+}
+
+func Test_scrapeFeeds_retryAfter(t *testing.T) {
+	backends := map[string]func(t *testing.T) (*state, database.User){
+		"memory": newTestState,
+		"sqlite": newSQLiteTestState,
+	}
+	retryAfter := map[string]func() (int, string){
+		"429 with seconds": func() (int, string) { return http.StatusTooManyRequests, "1" },
+		"503 with a date": func() (int, string) {
+			return http.StatusServiceUnavailable, time.Now().Add(time.Second).UTC().Format(http.TimeFormat)
+		},
+	}
+	for backend, newState := range backends {
+		for name, response := range retryAfter {
+			t.Run(backend+" "+name, func(t *testing.T) {
+				// most of the test is waiting for the backoff to pass
+				t.Parallel()
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/limited.xml" {
+						status, header := response()
+						w.Header().Set("Retry-After", header)
+						w.WriteHeader(status)
+						return
+					}
+					fmt.Fprint(w, `<rss><channel><title>Example</title></channel></rss>`)
+				}))
+				defer server.Close()
+
+				s, user := newState(t)
+				ctx := context.Background()
+				limited := addTestFeed(t, s, user, "Limited", server.URL+"/limited.xml")
+				addTestFeed(t, s, user, "Same host", server.URL+"/other.xml")
+				// the same server under another host name isn't backed off
+				addTestFeed(t, s, user, "Other host", strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/other.xml")
+
+				before := time.Now()
+				if _, err := scrapeFeeds(ctx, s, false); err != nil {
+					t.Fatalf("scrapeFeeds() error = %v", err)
+				}
+
+				feeds, err := s.db.GetFeeds(ctx)
+				if err != nil {
+					t.Fatalf("GetFeeds() error = %v", err)
+				}
+				var notBefore time.Time
+				for _, feed := range feeds {
+					if feed.ID != limited.ID {
+						continue
+					}
+					notBefore = feed.NotBefore.Time
+					if !feed.NotBefore.Valid || feed.NotBefore.Time.Before(before) || feed.NotBefore.Time.After(before.Add(2*time.Second)) {
+						t.Errorf("not_before = %v, want within the two seconds after %v", feed.NotBefore, before)
+					}
+				}
+
+				// every feed is due again, only the one on another host may be fetched
+				if _, err := s.db.RequestAllFeedsRefresh(ctx); err != nil {
+					t.Fatalf("RequestAllFeedsRefresh() error = %v", err)
+				}
+				if claimed := claimAll(t, s, "worker-one"); claimed != 1 {
+					t.Errorf("claimed %d feeds during the backoff, want only the feed on the other host", claimed)
+				}
+
+				// SQLite's CURRENT_TIMESTAMP has whole seconds
+				time.Sleep(time.Until(notBefore.Truncate(time.Second).Add(time.Second)))
+				if claimed := claimAll(t, s, "worker-two"); claimed != 2 {
+					t.Errorf("claimed %d feeds after the backoff, want both feeds of the backed off host", claimed)
+				}
+			})
+		}
+	}
+}
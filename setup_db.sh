@@ -1,9 +1,8 @@
 #!/bin/bash
 
-# Create the users table
-sudo -u postgres psql -d gator -c "CREATE TABLE IF NOT EXISTS users(id UUID PRIMARY KEY, created_at TIMESTAMP NOT NULL, updated_at TIMESTAMP NOT NULL, user_name varchar(255) NOT NULL UNIQUE);"
+# Create the gator database, the tables come from the migrations embedded in the binary
+sudo -u postgres psql -tc "SELECT 1 FROM pg_database WHERE datname = 'gator'" | grep -q 1 || sudo -u postgres psql -c "CREATE DATABASE gator;"
 
-# Create the feeds table
-sudo -u postgres psql -d gator -c "CREATE TABLE IF NOT EXISTS feeds (id UUID PRIMARY KEY, createdAt TIMESTAMP DEFAULT NOW() NOT NULL, updatedAt TIMESTAMP DEFAULT NOW() NOT NULL, feed_name VARCHAR(255) NOT NULL, feed_url VARCHAR(255) UNIQUE NOT NULL, user_id UUID NOT NULL, CONSTRAINT fk_feeds_users_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE);"
+go run . migrate up
 
-echo "Database tables created successfully!" 
\ No newline at end of file
+echo "Database is ready!"
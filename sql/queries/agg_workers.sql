@@ -0,0 +1,10 @@
+-- name: UpsertAggWorker :exec
+INSERT INTO agg_workers(worker_id, started_at, last_seen_at)
+VALUES($1, NOW(), NOW())
+ON CONFLICT (worker_id) DO UPDATE SET last_seen_at = NOW();
+
+-- name: DeleteAggWorker :exec
+DELETE FROM agg_workers WHERE worker_id = $1;
+
+-- name: CountLiveAggWorkers :one
+SELECT COUNT(*) FROM agg_workers WHERE last_seen_at > $1;
@@ -19,11 +19,127 @@ SELECT feed_name FROM feeds WHERE id = $1;
 -- name: GetFeeds :many
 SELECT * FROM feeds;
 
--- name: MarkFeedFetched :exec
-UPDATE feeds SET last_fetched_at = NOW(), updatedat = NOW();
+-- name: MarkFeedFetched :execrows
+-- Only the worker holding the lease may reschedule the feed, a worker whose lease ran out
+-- and was taken over updates nothing
+UPDATE feeds SET last_fetched_at = NOW(), next_fetch_at = $2, fetch_interval_seconds = $3,
+    leased_by = NULL, lease_expires_at = NULL, refresh_requested_at = NULL, updatedat = NOW()
+WHERE id = $1 AND leased_by = $4;
 
--- name: GetNextFeedToFetch :many
-SELECT id, feed_name, feed_url, last_fetched_at
+-- name: ClaimFeedsToFetch :many
+WITH due AS (
+    SELECT id
     FROM feeds
-    ORDER BY last_fetched_at DESC NULLS LAST
-    LIMIT 5; 
+    WHERE status = 'active'
+    AND (next_fetch_at IS NULL OR next_fetch_at <= NOW() OR refresh_requested_at IS NOT NULL)
+    AND (NOT sqlc.arg(only_requested)::boolean OR refresh_requested_at IS NOT NULL)
+    AND (not_before IS NULL OR not_before <= NOW())
+    AND (lease_expires_at IS NULL OR lease_expires_at <= NOW())
+    AND NOT EXISTS (
+        SELECT 1 FROM host_backoff
+        WHERE host_backoff.host = lower(substring(feeds.feed_url from '://([^/?#:]+)'))
+        AND host_backoff.not_before > NOW()
+    )
+    ORDER BY refresh_requested_at ASC NULLS LAST, next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE feeds SET leased_by = sqlc.arg(worker), lease_expires_at = sqlc.arg(lease_expires_at)
+FROM due
+WHERE feeds.id = due.id
+RETURNING feeds.id, feeds.feed_name, feeds.feed_url, feeds.last_fetched_at, feeds.fetch_interval_seconds,
+    feeds.ttl_minutes, feeds.skip_hours, feeds.skip_days, feeds.update_period, feeds.update_frequency,
+    feeds.cron_schedule, feeds.quiet_hours;
+
+-- name: RecordFeedNotFound :one
+UPDATE feeds SET consecutive_not_found = consecutive_not_found + 1, updatedat = NOW()
+WHERE id = $1
+RETURNING consecutive_not_found;
+
+-- name: ResetFeedNotFound :exec
+UPDATE feeds SET consecutive_not_found = 0
+WHERE id = $1 AND consecutive_not_found > 0;
+
+-- name: SetFeedStatus :exec
+UPDATE feeds SET status = $2, status_reason = $3, updatedat = NOW()
+WHERE id = $1;
+
+-- name: GetFeedsByStatus :many
+SELECT * FROM feeds WHERE status = $1 ORDER BY updatedat DESC;
+
+-- name: ReviveFeed :one
+UPDATE feeds SET status = 'active', status_reason = NULL, consecutive_not_found = 0, updatedat = NOW()
+WHERE feed_url = $1
+RETURNING *;
+
+-- name: SetFeedNotBefore :exec
+UPDATE feeds SET not_before = $2, updatedat = NOW()
+WHERE id = $1;
+
+-- name: UpdateFeedHints :exec
+UPDATE feeds SET ttl_minutes = $2, skip_hours = $3, skip_days = $4, update_period = $5, update_frequency = $6
+WHERE id = $1;
+
+-- name: SetFeedSchedule :execrows
+-- Only the settings that are given change, a NULL keeps what the feed has
+UPDATE feeds SET
+    cron_schedule = COALESCE(sqlc.narg(cron_schedule)::text, cron_schedule),
+    quiet_hours = COALESCE(sqlc.narg(quiet_hours)::text, quiet_hours),
+    updatedat = NOW()
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: ClearFeedSchedule :execrows
+UPDATE feeds SET cron_schedule = NULL, quiet_hours = NULL, updatedat = NOW()
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: GetFeedSchedule :one
+SELECT id, cron_schedule, quiet_hours FROM feeds
+WHERE feed_url = $1;
+
+-- name: SetFeedNextFetch :exec
+UPDATE feeds SET next_fetch_at = $2, updatedat = NOW()
+WHERE id = $1;
+
+-- name: SetFeedRetention :execrows
+-- Only the limits that are given change, a NULL keeps what the feed has
+UPDATE feeds SET
+    retention_keep_last = COALESCE(sqlc.narg(retention_keep_last)::int, retention_keep_last),
+    retention_max_age_days = COALESCE(sqlc.narg(retention_max_age_days)::int, retention_max_age_days),
+    updatedat = NOW()
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: ClearFeedRetention :execrows
+UPDATE feeds SET retention_keep_last = NULL, retention_max_age_days = NULL, updatedat = NOW()
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: SetFeedExtractArticles :execrows
+UPDATE feeds SET extract_articles = $2, updatedat = NOW()
+WHERE feed_url = $1;
+
+-- name: ReleaseFeedLeases :exec
+UPDATE feeds SET leased_by = NULL, lease_expires_at = NULL
+WHERE leased_by = $1;
+
+-- name: RequestFeedRefresh :execrows
+-- Paused and dead feeds are never claimed, so they aren't queued either
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE feed_url = $1
+AND status = 'active';
+
+-- name: RequestAllFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE status = 'active';
+
+-- name: RequestFollowedFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = NOW()
+WHERE status = 'active'
+AND id IN (SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = $1);
+
+-- name: RecordFeedSuccess :exec
+UPDATE feeds SET last_success_at = NOW(),
+    last_new_item_at = CASE WHEN sqlc.arg(had_new_items)::boolean THEN NOW() ELSE last_new_item_at END
+WHERE id = sqlc.arg(id);
+
+-- name: RecordFeedError :exec
+UPDATE feeds SET last_error = $2, last_error_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: CreateFetchLog :exec
+INSERT INTO fetch_log(id, feed_id, started_at, duration_ms, http_status, bytes, items_parsed, new_items, error)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetFeedStats :many
+SELECT
+    feeds.id,
+    feeds.feed_name,
+    feeds.feed_url,
+    COUNT(fetch_log.id)::int AS attempts,
+    (COUNT(fetch_log.id) FILTER (WHERE fetch_log.error IS NULL))::int AS successes,
+    COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY fetch_log.duration_ms), 0)::float8 AS median_duration_ms,
+    COALESCE(SUM(fetch_log.new_items), 0)::int AS new_items
+    FROM feeds
+    LEFT JOIN fetch_log ON fetch_log.feed_id = feeds.id AND fetch_log.started_at >= sqlc.arg(since)
+    WHERE sqlc.arg(feed_url)::text = '' OR feeds.feed_url = sqlc.arg(feed_url)::text
+    GROUP BY feeds.id, feeds.feed_name, feeds.feed_url
+    ORDER BY feeds.feed_name;
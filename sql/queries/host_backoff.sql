@@ -0,0 +1,6 @@
+-- name: SetHostBackoff :exec
+INSERT INTO host_backoff(host, not_before, updatedAt)
+VALUES($1, $2, NOW())
+ON CONFLICT (host) DO UPDATE
+    SET not_before = GREATEST(host_backoff.not_before, EXCLUDED.not_before),
+        updatedAt = NOW();
@@ -0,0 +1,24 @@
+-- name: MarkPostRead :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT sqlc.arg(user_id)::uuid, COALESCE(posts.canonical_post_id, posts.id), NOW()
+    FROM posts WHERE posts.id = sqlc.arg(post_id)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+    WHERE user_id = sqlc.arg(user_id)
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = sqlc.arg(post_id));
+
+-- name: MarkPostsReadBefore :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT DISTINCT sqlc.arg(user_id)::uuid, COALESCE(posts.canonical_post_id, posts.id), NOW()
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE COALESCE(posts.published_at, posts.created_at) < sqlc.arg(before)::timestamp
+    AND (
+        (sqlc.arg(feed_url)::text <> '' AND feeds.feed_url = sqlc.arg(feed_url)::text)
+        OR (sqlc.arg(feed_url)::text = '' AND posts.feed_id IN (
+            SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = sqlc.arg(user_id)::uuid
+        ))
+    )
+ON CONFLICT (user_id, post_id) DO NOTHING;
@@ -0,0 +1,8 @@
+-- name: CreatePostRevision :exec
+INSERT INTO post_revisions(id, post_id, revised_at, title, description, content)
+VALUES($1, $2, $3, $4, $5, $6);
+
+-- name: GetPostRevisions :many
+SELECT id, revised_at, title, description, content FROM post_revisions
+WHERE post_id = $1
+ORDER BY revised_at;
@@ -0,0 +1,18 @@
+-- name: StarPost :execrows
+INSERT INTO post_stars(user_id, post_id, starred_at)
+SELECT sqlc.arg(user_id)::uuid, COALESCE(posts.canonical_post_id, posts.id), NOW()
+    FROM posts WHERE posts.id = sqlc.arg(post_id)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+    WHERE user_id = sqlc.arg(user_id)
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = sqlc.arg(post_id));
+
+-- name: GetStarredPosts :many
+SELECT posts.id, posts.title, posts.url, posts.published_at, feeds.feed_name, post_stars.starred_at
+    FROM post_stars
+    INNER JOIN posts ON post_stars.post_id = posts.id
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE post_stars.user_id = $1
+    ORDER BY post_stars.starred_at DESC;
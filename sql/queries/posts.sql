@@ -0,0 +1,145 @@
+-- name: CreatePost :execrows
+INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id, canonical_url, fingerprint, canonical_post_id, content, author, guid)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (feed_id, url) DO NOTHING;
+
+-- name: FindCanonicalPost :one
+SELECT id FROM posts
+WHERE canonical_post_id IS NULL
+AND feed_id <> sqlc.arg(feed_id)
+AND (canonical_url = sqlc.arg(canonical_url) OR (sqlc.arg(fingerprint)::text <> '' AND fingerprint = sqlc.arg(fingerprint)::text))
+ORDER BY created_at
+LIMIT 1;
+
+-- name: GetPostsForUser :many
+SELECT
+    posts.id, posts.title, posts.url, posts.description, posts.published_at,
+    feeds.feed_name,
+    COALESCE((
+        SELECT string_agg(duplicate_feeds.feed_name, ', ' ORDER BY duplicate_feeds.feed_name)
+        FROM posts AS duplicates
+        INNER JOIN feeds AS duplicate_feeds ON duplicates.feed_id = duplicate_feeds.id
+        WHERE duplicates.canonical_post_id = posts.id
+    ), '')::text AS also_in,
+    EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id AND post_reads.user_id = sqlc.arg(user_id)
+    ) AS is_read
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.canonical_post_id IS NULL
+    AND (sqlc.arg(include_read)::boolean OR NOT EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id AND post_reads.user_id = sqlc.arg(user_id)
+    ))
+    AND EXISTS (
+        SELECT 1 FROM feed_follow
+        WHERE feed_follow.user_id = sqlc.arg(user_id)
+        AND (feed_follow.feed_id = posts.feed_id
+            OR feed_follow.feed_id IN (SELECT duplicates.feed_id FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id))
+    )
+    ORDER BY posts.published_at DESC NULLS LAST, posts.created_at DESC
+    LIMIT sqlc.arg(post_limit);
+
+-- name: SearchPosts :many
+SELECT
+    posts.id, posts.title, posts.url, posts.published_at, posts.author,
+    feeds.feed_name,
+    ts_rank(posts.search_vector, to_tsquery('english', sqlc.arg(query)::text))::float4 AS rank,
+    ts_headline('english', coalesce(posts.description, posts.content, posts.article_text, ''), to_tsquery('english', sqlc.arg(query)::text),
+        'MaxFragments=1, MaxWords=30, MinWords=10, StartSel=**, StopSel=**')::text AS snippet
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.search_vector @@ to_tsquery('english', sqlc.arg(query)::text)
+    AND (sqlc.arg(feed_url)::text = '' OR feeds.feed_url = sqlc.arg(feed_url)::text)
+    AND (posts.canonical_post_id IS NULL OR sqlc.arg(feed_url)::text <> '')
+    AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since)::timestamp)
+    AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until)::timestamp)
+    AND (sqlc.arg(author)::text = '' OR posts.author ILIKE '%' || sqlc.arg(author)::text || '%')
+    ORDER BY rank DESC, posts.published_at DESC NULLS LAST
+    LIMIT sqlc.arg(result_limit);
+
+-- name: GetPrunablePosts :many
+-- A post is pruned when it falls outside the newest keep_last posts of its feed or is older than max_age_days,
+-- a limit of 0 is no limit. Starred posts are always kept, and so is a post that duplicates still point at,
+-- it holds the read state of the story.
+WITH ranked AS (
+    SELECT
+        posts.id, posts.title, feeds.feed_name,
+        COALESCE(posts.published_at, posts.created_at) AS posted_at,
+        row_number() OVER (
+            PARTITION BY posts.feed_id
+            ORDER BY COALESCE(posts.published_at, posts.created_at) DESC, posts.created_at DESC
+        ) AS position,
+        COALESCE(feeds.retention_keep_last, sqlc.arg(default_keep_last)::int) AS keep_last,
+        COALESCE(feeds.retention_max_age_days, sqlc.arg(default_max_age_days)::int) AS max_age_days
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+)
+SELECT ranked.id, ranked.title, ranked.feed_name
+    FROM ranked
+    WHERE (
+        (ranked.keep_last > 0 AND ranked.position > ranked.keep_last)
+        OR (ranked.max_age_days > 0 AND ranked.posted_at < NOW() - make_interval(days => ranked.max_age_days))
+    )
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = ranked.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = ranked.id)
+    ORDER BY ranked.feed_name, ranked.posted_at;
+
+-- name: DeletePosts :execrows
+DELETE FROM posts
+    WHERE id = ANY(sqlc.arg(ids)::uuid[])
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id);
+
+-- name: GetPostByGUID :one
+SELECT id, title, description, content FROM posts
+WHERE feed_id = $1 AND guid = $2
+ORDER BY created_at
+LIMIT 1;
+
+-- name: SetPostGUID :exec
+-- Adopts a post stored before guids were kept, so its next edit is recognized
+UPDATE posts SET guid = $3
+WHERE feed_id = $1 AND url = $2 AND guid IS NULL;
+
+-- name: UpdatePostContent :exec
+UPDATE posts SET title = $2, description = $3, content = $4, fingerprint = $5, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetPost :one
+SELECT posts.id, posts.title, posts.url, posts.description, posts.content, posts.created_at, posts.updated_at,
+    posts.article_html, posts.article_text, posts.article_fetched_at, posts.article_error,
+    feeds.feed_name
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.id = $1;
+
+-- name: GetRecentPublishTimes :many
+-- Newest first, the publish history agg estimates a feed's posting rate from
+SELECT published_at FROM posts
+    WHERE feed_id = sqlc.arg(feed_id)
+    AND published_at IS NOT NULL
+    ORDER BY published_at DESC
+    LIMIT sqlc.arg(history_limit);
+
+-- name: GetPostsToExtract :many
+-- Newest first, so a feed that was just switched on gets its recent posts before its backlog
+SELECT posts.id, posts.url, feeds.feed_url
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE feeds.extract_articles
+    AND feeds.status = 'active'
+    AND posts.article_fetched_at IS NULL
+    AND posts.canonical_post_id IS NULL
+    ORDER BY posts.created_at DESC
+    LIMIT $1;
+
+-- name: SaveArticle :exec
+UPDATE posts SET article_html = $2, article_text = $3, article_fetched_at = NOW(), article_error = NULL
+WHERE id = $1;
+
+-- name: RecordArticleError :exec
+-- The post isn't tried again by agg, the article command can still fetch it
+UPDATE posts SET article_fetched_at = NOW(), article_error = $2
+WHERE id = $1;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'paused', 'dead'));
+ALTER TABLE feeds ADD COLUMN status_reason TEXT;
+ALTER TABLE feeds ADD COLUMN consecutive_not_found INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN consecutive_not_found;
+ALTER TABLE feeds DROP COLUMN status_reason;
+ALTER TABLE feeds DROP COLUMN status;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN not_before TIMESTAMP;
+
+CREATE TABLE host_backoff(
+    host VARCHAR(255) PRIMARY KEY,
+    not_before TIMESTAMP NOT NULL,
+    updatedAt TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS host_backoff;
+ALTER TABLE feeds DROP COLUMN not_before;
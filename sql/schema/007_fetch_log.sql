@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE fetch_log(
+    id UUID PRIMARY KEY,
+    feed_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    http_status INTEGER,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    items_parsed INTEGER NOT NULL DEFAULT 0,
+    new_items INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+
+    CONSTRAINT fk_fetch_log_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_fetch_log_feed_id_started_at ON fetch_log (feed_id, started_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS fetch_log;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN next_fetch_at TIMESTAMP;
+CREATE INDEX idx_feeds_next_fetch_at ON feeds (next_fetch_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_feeds_next_fetch_at;
+ALTER TABLE feeds DROP COLUMN next_fetch_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN ttl_minutes INTEGER;
+ALTER TABLE feeds ADD COLUMN skip_hours INTEGER[] NOT NULL DEFAULT '{}';
+ALTER TABLE feeds ADD COLUMN skip_days TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE feeds ADD COLUMN update_period TEXT;
+ALTER TABLE feeds ADD COLUMN update_frequency INTEGER;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN update_frequency;
+ALTER TABLE feeds DROP COLUMN update_period;
+ALTER TABLE feeds DROP COLUMN skip_days;
+ALTER TABLE feeds DROP COLUMN skip_hours;
+ALTER TABLE feeds DROP COLUMN ttl_minutes;
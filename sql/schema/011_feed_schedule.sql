@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN cron_schedule TEXT;
+ALTER TABLE feeds ADD COLUMN quiet_hours TEXT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN quiet_hours;
+ALTER TABLE feeds DROP COLUMN cron_schedule;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN leased_by TEXT;
+ALTER TABLE feeds ADD COLUMN lease_expires_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN lease_expires_at;
+ALTER TABLE feeds DROP COLUMN leased_by;
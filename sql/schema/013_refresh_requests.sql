@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN refresh_requested_at TIMESTAMP;
+
+CREATE TABLE agg_workers(
+    worker_id TEXT PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS agg_workers;
+ALTER TABLE feeds DROP COLUMN refresh_requested_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN last_success_at TIMESTAMP;
+ALTER TABLE feeds ADD COLUMN last_new_item_at TIMESTAMP;
+ALTER TABLE feeds ADD COLUMN last_error TEXT;
+ALTER TABLE feeds ADD COLUMN last_error_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN last_error_at;
+ALTER TABLE feeds DROP COLUMN last_error;
+ALTER TABLE feeds DROP COLUMN last_new_item_at;
+ALTER TABLE feeds DROP COLUMN last_success_at;
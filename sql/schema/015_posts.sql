@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE posts(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id UUID NOT NULL,
+    canonical_url TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    canonical_post_id UUID,
+
+    CONSTRAINT fk_posts_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_posts_posts_canonical_post_id FOREIGN KEY (canonical_post_id)
+        REFERENCES posts (id)
+        ON DELETE SET NULL,
+
+    CONSTRAINT uq_posts_feed_id_url UNIQUE (feed_id, url)
+);
+
+CREATE INDEX idx_posts_canonical_url ON posts (canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts (fingerprint);
+CREATE INDEX idx_posts_canonical_post_id ON posts (canonical_post_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS posts;
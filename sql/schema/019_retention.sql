@@ -0,0 +1,8 @@
+-- +goose Up
+-- NULL falls back to the retention in the config, 0 keeps the posts of the feed forever
+ALTER TABLE feeds ADD COLUMN retention_keep_last INTEGER;
+ALTER TABLE feeds ADD COLUMN retention_max_age_days INTEGER;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN retention_max_age_days;
+ALTER TABLE feeds DROP COLUMN retention_keep_last;
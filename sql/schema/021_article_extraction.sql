@@ -0,0 +1,39 @@
+-- +goose Up
+-- Feeds that only publish a teaser can opt in to fetching the linked article. article_fetched_at
+-- is set after every attempt, article_error tells a failed attempt apart from a finished one.
+ALTER TABLE feeds ADD COLUMN extract_articles BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE posts ADD COLUMN article_html TEXT;
+ALTER TABLE posts ADD COLUMN article_text TEXT;
+ALTER TABLE posts ADD COLUMN article_fetched_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN article_error TEXT;
+
+-- The extracted text is searched too, with the lowest weight
+DROP INDEX IF EXISTS idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(article_text, '')), 'D')
+) STORED;
+
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_posts_search_vector;
+ALTER TABLE posts DROP COLUMN search_vector;
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+
+ALTER TABLE posts DROP COLUMN article_error;
+ALTER TABLE posts DROP COLUMN article_fetched_at;
+ALTER TABLE posts DROP COLUMN article_text;
+ALTER TABLE posts DROP COLUMN article_html;
+
+ALTER TABLE feeds DROP COLUMN extract_articles;
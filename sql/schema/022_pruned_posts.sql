@@ -0,0 +1,18 @@
+-- +goose Up
+-- Posts removed by prune are remembered, so an item still in the feed isn't stored again on the next fetch
+CREATE TABLE pruned_posts(
+    feed_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    guid TEXT,
+    pruned_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (feed_id, url),
+    CONSTRAINT fk_pruned_posts_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pruned_posts_feed_id_guid ON pruned_posts (feed_id, guid);
+
+-- +goose Down
+DROP TABLE IF EXISTS pruned_posts;
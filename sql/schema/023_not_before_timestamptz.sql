@@ -0,0 +1,8 @@
+-- +goose Up
+-- A Retry-After date is absolute, with a time zone the stored time no longer depends on the zone of agg or the server
+ALTER TABLE feeds ALTER COLUMN not_before TYPE TIMESTAMPTZ;
+ALTER TABLE host_backoff ALTER COLUMN not_before TYPE TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE host_backoff ALTER COLUMN not_before TYPE TIMESTAMP;
+ALTER TABLE feeds ALTER COLUMN not_before TYPE TIMESTAMP;
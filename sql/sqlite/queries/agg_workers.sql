@@ -0,0 +1,10 @@
+-- name: UpsertAggWorker :exec
+INSERT INTO agg_workers(worker_id, started_at, last_seen_at)
+VALUES(?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+ON CONFLICT (worker_id) DO UPDATE SET last_seen_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteAggWorker :exec
+DELETE FROM agg_workers WHERE worker_id = ?;
+
+-- name: CountLiveAggWorkers :one
+SELECT COUNT(*) FROM agg_workers WHERE last_seen_at > ?;
@@ -0,0 +1,28 @@
+-- SQLite has no INSERT inside a WITH, so CreateFeedFollow is an insert followed by GetFeedFollow
+
+-- name: CreateFeedFollow :one
+INSERT INTO feed_follow(id, createdAt, updatedAt, user_id, feed_id)
+VALUES(?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetFeedFollow :one
+SELECT
+    feed_follow.*,
+    feeds.feed_name AS feed_name,
+    users.user_name AS user_name
+    FROM feed_follow
+    INNER JOIN users ON feed_follow.user_id = users.id
+    INNER JOIN feeds ON feed_follow.feed_id = feeds.id
+    WHERE feed_follow.id = ?;
+
+-- name: GetFeedFollowsForUser :many
+SELECT
+    users.user_name, feeds.feed_name FROM feed_follow
+    INNER JOIN users ON feed_follow.user_id = users.id
+    INNER JOIN feeds ON feed_follow.feed_id = feeds.id
+    WHERE feed_follow.user_id = ?;
+
+-- name: DeleteFeedFollowRecord :exec
+DELETE FROM feed_follow
+WHERE feed_follow.user_id = @user_id
+AND feed_follow.feed_id = (SELECT feeds.id FROM feeds WHERE feeds.feed_url = @feed_url);
@@ -0,0 +1,138 @@
+-- name: CreateFeed :one
+INSERT INTO feeds(id, createdAt, updatedAt, feed_name, feed_url, user_id)
+VALUES(?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetFeedByURL :one
+SELECT id FROM feeds WHERE feed_url = ?;
+
+-- name: GetFeedNameById :one
+SELECT feed_name FROM feeds WHERE id = ?;
+
+-- name: GetFeeds :many
+SELECT * FROM feeds;
+
+-- name: MarkFeedFetched :execrows
+-- Only the worker holding the lease may reschedule the feed, a worker whose lease ran out
+-- and was taken over updates nothing
+UPDATE feeds SET last_fetched_at = CURRENT_TIMESTAMP, next_fetch_at = @next_fetch_at, fetch_interval_seconds = @fetch_interval_seconds,
+    leased_by = NULL, lease_expires_at = NULL, refresh_requested_at = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE id = @id AND leased_by = @leased_by;
+
+-- name: ClaimFeedsToFetch :many
+-- SQLite has a single writer, so the UPDATE alone makes the claim atomic.
+-- feed_host is registered by the store, see hostOf.
+UPDATE feeds SET leased_by = @worker, lease_expires_at = @lease_expires_at
+WHERE id IN (
+    SELECT due.id
+    FROM feeds AS due
+    WHERE due.status = 'active'
+    AND (due.next_fetch_at IS NULL OR due.next_fetch_at <= CURRENT_TIMESTAMP OR due.refresh_requested_at IS NOT NULL)
+    AND (CAST(@only_requested AS BOOLEAN) = FALSE OR due.refresh_requested_at IS NOT NULL)
+    AND (due.not_before IS NULL OR due.not_before <= CURRENT_TIMESTAMP)
+    AND (due.lease_expires_at IS NULL OR due.lease_expires_at <= CURRENT_TIMESTAMP)
+    AND NOT EXISTS (
+        SELECT 1 FROM host_backoff
+        WHERE host_backoff.host = feed_host(due.feed_url)
+        AND host_backoff.not_before > CURRENT_TIMESTAMP
+    )
+    ORDER BY due.refresh_requested_at ASC NULLS LAST, due.next_fetch_at ASC NULLS FIRST, due.last_fetched_at ASC NULLS FIRST
+    LIMIT @batch_size
+)
+RETURNING id, feed_name, feed_url, last_fetched_at, fetch_interval_seconds,
+    ttl_minutes, skip_hours, skip_days, update_period, update_frequency,
+    cron_schedule, quiet_hours;
+
+-- name: RecordFeedNotFound :one
+UPDATE feeds SET consecutive_not_found = consecutive_not_found + 1, updatedAt = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING consecutive_not_found;
+
+-- name: ResetFeedNotFound :exec
+UPDATE feeds SET consecutive_not_found = 0
+WHERE id = ? AND consecutive_not_found > 0;
+
+-- name: SetFeedStatus :exec
+UPDATE feeds SET status = @status, status_reason = @status_reason, updatedAt = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: GetFeedsByStatus :many
+SELECT * FROM feeds WHERE status = ? ORDER BY updatedAt DESC;
+
+-- name: ReviveFeed :one
+UPDATE feeds SET status = 'active', status_reason = NULL, consecutive_not_found = 0, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = ?
+RETURNING *;
+
+-- name: SetFeedNotBefore :exec
+UPDATE feeds SET not_before = @not_before, updatedAt = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: UpdateFeedHints :exec
+UPDATE feeds SET ttl_minutes = @ttl_minutes, skip_hours = @skip_hours, skip_days = @skip_days,
+    update_period = @update_period, update_frequency = @update_frequency
+WHERE id = @id;
+
+-- name: SetFeedSchedule :execrows
+-- Only the settings that are given change, a NULL keeps what the feed has
+UPDATE feeds SET
+    cron_schedule = COALESCE(sqlc.narg(cron_schedule), cron_schedule),
+    quiet_hours = COALESCE(sqlc.narg(quiet_hours), quiet_hours),
+    updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: ClearFeedSchedule :execrows
+UPDATE feeds SET cron_schedule = NULL, quiet_hours = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: GetFeedSchedule :one
+SELECT id, cron_schedule, quiet_hours FROM feeds
+WHERE feed_url = ?;
+
+-- name: SetFeedNextFetch :exec
+UPDATE feeds SET next_fetch_at = @next_fetch_at, updatedAt = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: SetFeedRetention :execrows
+-- Only the limits that are given change, a NULL keeps what the feed has
+UPDATE feeds SET
+    retention_keep_last = COALESCE(sqlc.narg(retention_keep_last), retention_keep_last),
+    retention_max_age_days = COALESCE(sqlc.narg(retention_max_age_days), retention_max_age_days),
+    updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: ClearFeedRetention :execrows
+UPDATE feeds SET retention_keep_last = NULL, retention_max_age_days = NULL, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = sqlc.arg(feed_url);
+
+-- name: SetFeedExtractArticles :execrows
+UPDATE feeds SET extract_articles = @extract_articles, updatedAt = CURRENT_TIMESTAMP
+WHERE feed_url = @feed_url;
+
+-- name: ReleaseFeedLeases :exec
+UPDATE feeds SET leased_by = NULL, lease_expires_at = NULL
+WHERE leased_by = ?;
+
+-- name: RequestFeedRefresh :execrows
+-- Paused and dead feeds are never claimed, so they aren't queued either
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE feed_url = ?
+AND status = 'active';
+
+-- name: RequestAllFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE status = 'active';
+
+-- name: RequestFollowedFeedsRefresh :execrows
+UPDATE feeds SET refresh_requested_at = CURRENT_TIMESTAMP
+WHERE status = 'active'
+AND id IN (SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = ?);
+
+-- name: RecordFeedSuccess :exec
+UPDATE feeds SET last_success_at = CURRENT_TIMESTAMP,
+    last_new_item_at = CASE WHEN CAST(@had_new_items AS BOOLEAN) THEN CURRENT_TIMESTAMP ELSE last_new_item_at END
+WHERE id = @id;
+
+-- name: RecordFeedError :exec
+UPDATE feeds SET last_error = @last_error, last_error_at = CURRENT_TIMESTAMP
+WHERE id = @id;
@@ -0,0 +1,27 @@
+-- name: CreateFetchLog :exec
+INSERT INTO fetch_log(id, feed_id, started_at, duration_ms, http_status, bytes, items_parsed, new_items, error)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetFeedStats :many
+-- SQLite has no percentile_cont, the median is the average of the middle one or two durations
+WITH logs AS (
+    SELECT
+        fetch_log.id, fetch_log.feed_id, fetch_log.duration_ms, fetch_log.new_items, fetch_log.error,
+        row_number() OVER (PARTITION BY fetch_log.feed_id ORDER BY fetch_log.duration_ms) AS position,
+        COUNT(*) OVER (PARTITION BY fetch_log.feed_id) AS total
+    FROM fetch_log
+    WHERE fetch_log.started_at >= @since
+)
+SELECT
+    feeds.id,
+    feeds.feed_name,
+    feeds.feed_url,
+    CAST(COUNT(logs.id) AS INTEGER) AS attempts,
+    CAST(COUNT(logs.id) FILTER (WHERE logs.error IS NULL) AS INTEGER) AS successes,
+    CAST(COALESCE(AVG(logs.duration_ms) FILTER (WHERE logs.position IN ((logs.total + 1) / 2, (logs.total + 2) / 2)), 0) AS REAL) AS median_duration_ms,
+    CAST(COALESCE(SUM(logs.new_items), 0) AS INTEGER) AS new_items
+    FROM feeds
+    LEFT JOIN logs ON logs.feed_id = feeds.id
+    WHERE CAST(@feed_url AS TEXT) = '' OR feeds.feed_url = CAST(@feed_url AS TEXT)
+    GROUP BY feeds.id, feeds.feed_name, feeds.feed_url
+    ORDER BY feeds.feed_name;
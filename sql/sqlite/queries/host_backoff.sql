@@ -0,0 +1,6 @@
+-- name: SetHostBackoff :exec
+INSERT INTO host_backoff(host, not_before, updatedAt)
+VALUES(?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (host) DO UPDATE
+    SET not_before = MAX(host_backoff.not_before, excluded.not_before),
+        updatedAt = CURRENT_TIMESTAMP;
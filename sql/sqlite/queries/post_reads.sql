@@ -0,0 +1,24 @@
+-- name: MarkPostRead :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT @user_id, COALESCE(posts.canonical_post_id, posts.id), CURRENT_TIMESTAMP
+    FROM posts WHERE posts.id = @post_id
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+    WHERE user_id = @user_id
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = @post_id);
+
+-- name: MarkPostsReadBefore :execrows
+INSERT INTO post_reads(user_id, post_id, read_at)
+SELECT DISTINCT @user_id, COALESCE(posts.canonical_post_id, posts.id), CURRENT_TIMESTAMP
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE COALESCE(posts.published_at, posts.created_at) < @before
+    AND (
+        (CAST(@feed_url AS TEXT) <> '' AND feeds.feed_url = CAST(@feed_url AS TEXT))
+        OR (CAST(@feed_url AS TEXT) = '' AND posts.feed_id IN (
+            SELECT feed_follow.feed_id FROM feed_follow WHERE feed_follow.user_id = @user_id
+        ))
+    )
+ON CONFLICT (user_id, post_id) DO NOTHING;
@@ -0,0 +1,8 @@
+-- name: CreatePostRevision :exec
+INSERT INTO post_revisions(id, post_id, revised_at, title, description, content)
+VALUES(?, ?, ?, ?, ?, ?);
+
+-- name: GetPostRevisions :many
+SELECT id, revised_at, title, description, content FROM post_revisions
+WHERE post_id = ?
+ORDER BY revised_at;
@@ -0,0 +1,18 @@
+-- name: StarPost :execrows
+INSERT INTO post_stars(user_id, post_id, starred_at)
+SELECT @user_id, COALESCE(posts.canonical_post_id, posts.id), CURRENT_TIMESTAMP
+    FROM posts WHERE posts.id = @post_id
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+    WHERE user_id = @user_id
+    AND post_id = (SELECT COALESCE(posts.canonical_post_id, posts.id) FROM posts WHERE posts.id = @post_id);
+
+-- name: GetStarredPosts :many
+SELECT posts.id, posts.title, posts.url, posts.published_at, feeds.feed_name, post_stars.starred_at
+    FROM post_stars
+    INNER JOIN posts ON post_stars.post_id = posts.id
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE post_stars.user_id = ?
+    ORDER BY post_stars.starred_at DESC;
@@ -0,0 +1,129 @@
+-- name: CreatePost :execrows
+INSERT INTO posts(id, created_at, updated_at, title, url, description, published_at, feed_id, canonical_url, fingerprint, canonical_post_id, content, author, guid)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (feed_id, url) DO NOTHING;
+
+-- name: FindCanonicalPost :one
+SELECT id FROM posts
+WHERE canonical_post_id IS NULL
+AND feed_id <> @feed_id
+AND (canonical_url = @canonical_url OR (CAST(@fingerprint AS TEXT) <> '' AND fingerprint = CAST(@fingerprint AS TEXT)))
+ORDER BY created_at
+LIMIT 1;
+
+-- name: GetPostsForUser :many
+SELECT
+    posts.id, posts.title, posts.url, posts.description, posts.published_at,
+    feeds.feed_name,
+    CAST(COALESCE((
+        SELECT group_concat(duplicate_feeds.feed_name, ', ')
+        FROM (
+            SELECT feeds.feed_name FROM posts AS duplicates
+            INNER JOIN feeds ON duplicates.feed_id = feeds.id
+            WHERE duplicates.canonical_post_id = posts.id
+            ORDER BY feeds.feed_name
+        ) AS duplicate_feeds
+    ), '') AS TEXT) AS also_in,
+    EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id AND post_reads.user_id = @user_id
+    ) AS is_read
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.canonical_post_id IS NULL
+    AND (CAST(@include_read AS BOOLEAN) = TRUE OR posts.id NOT IN (
+        SELECT post_reads.post_id FROM post_reads WHERE post_reads.user_id = @user_id
+    ))
+    AND EXISTS (
+        SELECT 1 FROM feed_follow
+        WHERE feed_follow.user_id = @user_id
+        AND (feed_follow.feed_id = posts.feed_id
+            OR feed_follow.feed_id IN (SELECT duplicates.feed_id FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id))
+    )
+    ORDER BY posts.published_at DESC NULLS LAST, posts.created_at DESC
+    LIMIT @post_limit;
+
+-- name: GetPrunablePosts :many
+-- A post is pruned when it falls outside the newest keep_last posts of its feed or is older than max_age_days,
+-- a limit of 0 is no limit. Starred posts are always kept, and so is a post that duplicates still point at,
+-- it holds the read state of the story.
+WITH ranked AS (
+    SELECT
+        posts.id, posts.title, feeds.feed_name,
+        COALESCE(posts.published_at, posts.created_at) AS posted_at,
+        row_number() OVER (
+            PARTITION BY posts.feed_id
+            ORDER BY COALESCE(posts.published_at, posts.created_at) DESC, posts.created_at DESC
+        ) AS position,
+        COALESCE(feeds.retention_keep_last, CAST(@default_keep_last AS INTEGER)) AS keep_last,
+        COALESCE(feeds.retention_max_age_days, CAST(@default_max_age_days AS INTEGER)) AS max_age_days
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+)
+SELECT ranked.id, ranked.title, ranked.feed_name
+    FROM ranked
+    WHERE (
+        (ranked.keep_last > 0 AND ranked.position > ranked.keep_last)
+        OR (ranked.max_age_days > 0 AND ranked.posted_at < datetime('now', '-' || ranked.max_age_days || ' days'))
+    )
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = ranked.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = ranked.id)
+    ORDER BY ranked.feed_name, ranked.posted_at;
+
+-- name: DeletePosts :execrows
+DELETE FROM posts
+    WHERE id IN (sqlc.slice(ids))
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id);
+
+-- name: GetPostByGUID :one
+SELECT id, title, description, content FROM posts
+WHERE feed_id = ? AND guid = ?
+ORDER BY created_at
+LIMIT 1;
+
+-- name: SetPostGUID :exec
+-- Adopts a post stored before guids were kept, so its next edit is recognized
+UPDATE posts SET guid = @guid
+WHERE feed_id = @feed_id AND url = @url AND guid IS NULL;
+
+-- name: UpdatePostContent :exec
+UPDATE posts SET title = ?, description = ?, content = ?, fingerprint = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetPost :one
+SELECT posts.id, posts.title, posts.url, posts.description, posts.content, posts.created_at, posts.updated_at,
+    posts.article_html, posts.article_text, posts.article_fetched_at, posts.article_error,
+    feeds.feed_name
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE posts.id = ?;
+
+-- name: GetRecentPublishTimes :many
+-- Newest first, the publish history agg estimates a feed's posting rate from
+SELECT published_at FROM posts
+    WHERE feed_id = @feed_id
+    AND published_at IS NOT NULL
+    ORDER BY published_at DESC
+    LIMIT @history_limit;
+
+-- name: GetPostsToExtract :many
+-- Newest first, so a feed that was just switched on gets its recent posts before its backlog
+SELECT posts.id, posts.url, feeds.feed_url
+    FROM posts
+    INNER JOIN feeds ON posts.feed_id = feeds.id
+    WHERE feeds.extract_articles
+    AND feeds.status = 'active'
+    AND posts.article_fetched_at IS NULL
+    AND posts.canonical_post_id IS NULL
+    ORDER BY posts.created_at DESC
+    LIMIT ?;
+
+-- name: SaveArticle :exec
+UPDATE posts SET article_html = @article_html, article_text = @article_text, article_fetched_at = CURRENT_TIMESTAMP, article_error = NULL
+WHERE id = @id;
+
+-- name: RecordArticleError :exec
+-- The post isn't tried again by agg, the article command can still fetch it
+UPDATE posts SET article_fetched_at = CURRENT_TIMESTAMP, article_error = @article_error
+WHERE id = @id;
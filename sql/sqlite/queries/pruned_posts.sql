@@ -0,0 +1,16 @@
+-- name: RecordPrunedPosts :exec
+-- Starred posts and posts with duplicates are never deleted, so they aren't remembered either
+INSERT INTO pruned_posts (feed_id, url, guid, pruned_at)
+SELECT posts.feed_id, posts.url, posts.guid, CURRENT_TIMESTAMP
+    FROM posts
+    WHERE posts.id IN (sqlc.slice(ids))
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM posts AS duplicates WHERE duplicates.canonical_post_id = posts.id)
+ON CONFLICT (feed_id, url) DO UPDATE SET guid = excluded.guid, pruned_at = excluded.pruned_at;
+
+-- name: IsPostPruned :one
+SELECT CAST(EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE feed_id = @feed_id
+    AND (url = @url OR (CAST(@guid AS TEXT) <> '' AND guid = CAST(@guid AS TEXT)))
+) AS BOOLEAN);
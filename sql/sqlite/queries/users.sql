@@ -0,0 +1,19 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, user_name)
+VALUES(?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetUsers :many
+SELECT * FROM users;
+
+-- name: DeleteUser :exec
+DELETE FROM users;
+
+-- name: GetUser :one
+SELECT id, created_at, updated_at, user_name FROM users WHERE user_name = ?;
+
+-- name: GetUserByName :one
+SELECT id FROM users WHERE user_name = ?;
+
+-- name: GetUserNameById :one
+SELECT user_name FROM users WHERE id = ?;
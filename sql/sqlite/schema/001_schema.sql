@@ -0,0 +1,167 @@
+-- +goose Up
+-- The SQLite schema matches sql/schema up to 019_retention.sql.
+-- Postgres INTEGER columns are declared INT4 so sqlc generates the same Go types for both backends,
+-- arrays are stored as JSON text and the tsvector column is replaced by the posts_fts table.
+CREATE TABLE users(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE feeds(
+    id UUID PRIMARY KEY,
+    createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    feed_name TEXT NOT NULL,
+    feed_url TEXT NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    last_fetched_at TIMESTAMP,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'dead')),
+    status_reason TEXT,
+    consecutive_not_found INT4 NOT NULL DEFAULT 0,
+    not_before TIMESTAMP,
+    next_fetch_at TIMESTAMP,
+    fetch_interval_seconds INT4,
+    ttl_minutes INT4,
+    skip_hours TEXT NOT NULL DEFAULT '[]',
+    skip_days TEXT NOT NULL DEFAULT '[]',
+    update_period TEXT,
+    update_frequency INT4,
+    cron_schedule TEXT,
+    quiet_hours TEXT,
+    leased_by TEXT,
+    lease_expires_at TIMESTAMP,
+    refresh_requested_at TIMESTAMP,
+    last_success_at TIMESTAMP,
+    last_new_item_at TIMESTAMP,
+    last_error TEXT,
+    last_error_at TIMESTAMP,
+    retention_keep_last INT4,
+    retention_max_age_days INT4,
+
+    CONSTRAINT fk_feeds_users_user_id FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_feeds_next_fetch_at ON feeds (next_fetch_at);
+
+CREATE TABLE feed_follow(
+    id UUID PRIMARY KEY,
+    createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    feed_id UUID NOT NULL,
+
+    CONSTRAINT fk_feed_follow_users_user_id FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_feed_follow_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE host_backoff(
+    host TEXT PRIMARY KEY,
+    not_before TIMESTAMP NOT NULL,
+    updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE fetch_log(
+    id UUID PRIMARY KEY,
+    feed_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    duration_ms INT4 NOT NULL,
+    http_status INT4,
+    bytes INTEGER NOT NULL DEFAULT 0,
+    items_parsed INT4 NOT NULL DEFAULT 0,
+    new_items INT4 NOT NULL DEFAULT 0,
+    error TEXT,
+
+    CONSTRAINT fk_fetch_log_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_fetch_log_feed_id_started_at ON fetch_log (feed_id, started_at);
+
+CREATE TABLE agg_workers(
+    worker_id TEXT PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE posts(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id UUID NOT NULL,
+    canonical_url TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    canonical_post_id UUID,
+    content TEXT,
+    author TEXT,
+
+    CONSTRAINT fk_posts_feeds_feed_id FOREIGN KEY (feed_id)
+        REFERENCES feeds (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_posts_posts_canonical_post_id FOREIGN KEY (canonical_post_id)
+        REFERENCES posts (id)
+        ON DELETE SET NULL,
+
+    CONSTRAINT uq_posts_feed_id_url UNIQUE (feed_id, url)
+);
+
+CREATE INDEX idx_posts_canonical_url ON posts (canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts (fingerprint);
+CREATE INDEX idx_posts_canonical_post_id ON posts (canonical_post_id);
+
+CREATE TABLE post_reads(
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    read_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, post_id),
+
+    CONSTRAINT fk_post_reads_users_user_id FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_post_reads_posts_post_id FOREIGN KEY (post_id)
+        REFERENCES posts (id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE post_stars(
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    starred_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id, post_id),
+
+    CONSTRAINT fk_post_stars_users_user_id FOREIGN KEY (user_id)
+        REFERENCES users (id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_post_stars_posts_post_id FOREIGN KEY (post_id)
+        REFERENCES posts (id)
+        ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS post_stars;
+DROP TABLE IF EXISTS post_reads;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS agg_workers;
+DROP TABLE IF EXISTS fetch_log;
+DROP TABLE IF EXISTS host_backoff;
+DROP TABLE IF EXISTS feed_follow;
+DROP TABLE IF EXISTS feeds;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Full-text index over posts, kept in sync by triggers. It stores its own copy of the text
+-- because the rowid of a table with a UUID key is not stable across VACUUM.
+CREATE VIRTUAL TABLE posts_fts USING fts5(
+    post_id UNINDEXED, title, description, content,
+    tokenize = 'porter unicode61'
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(post_id, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+    DELETE FROM posts_fts WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, description, content ON posts BEGIN
+    UPDATE posts_fts SET title = new.title, description = new.description, content = new.content
+    WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS posts_fts_update;
+DROP TRIGGER IF EXISTS posts_fts_delete;
+DROP TRIGGER IF EXISTS posts_fts_insert;
+DROP TABLE IF EXISTS posts_fts;
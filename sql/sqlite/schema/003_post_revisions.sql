@@ -0,0 +1,25 @@
+-- +goose Up
+-- Matches sql/schema/020_post_revisions.sql
+ALTER TABLE posts ADD COLUMN guid TEXT;
+
+CREATE INDEX idx_posts_feed_id_guid ON posts (feed_id, guid);
+
+CREATE TABLE post_revisions(
+    id UUID PRIMARY KEY,
+    post_id UUID NOT NULL,
+    revised_at TIMESTAMP NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    content TEXT,
+
+    CONSTRAINT fk_post_revisions_posts_post_id FOREIGN KEY (post_id)
+        REFERENCES posts (id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_revisions_post_id ON post_revisions (post_id, revised_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS post_revisions;
+DROP INDEX IF EXISTS idx_posts_feed_id_guid;
+ALTER TABLE posts DROP COLUMN guid;
@@ -0,0 +1,84 @@
+-- +goose Up
+-- Matches sql/schema/021_article_extraction.sql. FTS5 tables can't gain a column,
+-- so posts_fts is rebuilt with the article text.
+ALTER TABLE feeds ADD COLUMN extract_articles BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE posts ADD COLUMN article_html TEXT;
+ALTER TABLE posts ADD COLUMN article_text TEXT;
+ALTER TABLE posts ADD COLUMN article_fetched_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN article_error TEXT;
+
+DROP TRIGGER IF EXISTS posts_fts_update;
+DROP TRIGGER IF EXISTS posts_fts_delete;
+DROP TRIGGER IF EXISTS posts_fts_insert;
+DROP TABLE IF EXISTS posts_fts;
+
+CREATE VIRTUAL TABLE posts_fts USING fts5(
+    post_id UNINDEXED, title, description, content, article,
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO posts_fts(post_id, title, description, content, article)
+SELECT id, title, description, content, article_text FROM posts;
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(post_id, title, description, content, article)
+    VALUES (new.id, new.title, new.description, new.content, new.article_text);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+    DELETE FROM posts_fts WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, description, content, article_text ON posts BEGIN
+    UPDATE posts_fts SET title = new.title, description = new.description, content = new.content,
+        article = new.article_text
+    WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS posts_fts_update;
+DROP TRIGGER IF EXISTS posts_fts_delete;
+DROP TRIGGER IF EXISTS posts_fts_insert;
+DROP TABLE IF EXISTS posts_fts;
+
+CREATE VIRTUAL TABLE posts_fts USING fts5(
+    post_id UNINDEXED, title, description, content,
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO posts_fts(post_id, title, description, content)
+SELECT id, title, description, content FROM posts;
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_fts(post_id, title, description, content)
+    VALUES (new.id, new.title, new.description, new.content);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_delete AFTER DELETE ON posts BEGIN
+    DELETE FROM posts_fts WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_fts_update AFTER UPDATE OF title, description, content ON posts BEGIN
+    UPDATE posts_fts SET title = new.title, description = new.description, content = new.content
+    WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+ALTER TABLE posts DROP COLUMN article_error;
+ALTER TABLE posts DROP COLUMN article_fetched_at;
+ALTER TABLE posts DROP COLUMN article_text;
+ALTER TABLE posts DROP COLUMN article_html;
+
+ALTER TABLE feeds DROP COLUMN extract_articles;
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+)
+
+// handlerStar bookmarks a post for the user, a duplicate post is starred through its canonical post
+func handlerStar(s *state, cmd command, user database.User) error {
+	postID, err := parsePostID(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	starred, err := s.db.StarPost(ctx, database.StarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("error in starring the post: %w", err)
+	}
+
+	if starred == 0 {
+		fmt.Println("Post was already starred or doesn't exist")
+		return nil
+	}
+
+	fmt.Println("Post starred")
+	return nil
+}
+
+func handlerUnstar(s *state, cmd command, user database.User) error {
+	postID, err := parsePostID(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	unstarred, err := s.db.UnstarPost(ctx, database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("error in unstarring the post: %w", err)
+	}
+
+	if unstarred == 0 {
+		fmt.Println("Post was not starred")
+		return nil
+	}
+
+	fmt.Println("Post unstarred")
+	return nil
+}
+
+func handlerStarred(s *state, cmd command, user database.User) error {
+	ctx := context.Background()
+	posts, err := s.db.GetStarredPosts(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("error in fetching the starred posts: %w", err)
+	}
+
+	if len(posts) == 0 {
+		fmt.Println("No starred posts")
+		return nil
+	}
+
+	for _, post := range posts {
+		fmt.Println("Post ID:", post.ID)
+		fmt.Println("Title:", post.Title)
+		fmt.Println("Link:", post.Url)
+		fmt.Println("Feed:", post.FeedName)
+		if post.PublishedAt.Valid {
+			fmt.Println("Published:", post.PublishedAt.Time)
+		}
+		fmt.Println("Starred:", post.StarredAt.Format("2006-01-02 15:04"))
+		fmt.Println("----------------------------------------")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/database"
+	"github.com/google/uuid"
+)
+
+func Test_handlerStar(t *testing.T) {
+	s, user := newTestState(t)
+	ctx := context.Background()
+	feed := addTestFeed(t, s, user, "News", "https://news.example/rss")
+	if _, err := captureOutput(t, func() error {
+		return handlerFollow(s, command{name: "follow", args: []string{feed.FeedUrl}}, user)
+	}); err != nil {
+		t.Fatalf("handlerFollow() error = %v", err)
+	}
+	if _, err := savePosts(ctx, s, feed.ID, testItems(2)); err != nil {
+		t.Fatalf("savePosts() error = %v", err)
+	}
+	posts, err := s.db.GetPostsForUser(ctx, database.GetPostsForUserParams{UserID: user.ID, PostLimit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForUser() error = %v", err)
+	}
+	id := posts[0].ID.String()
+
+	steps := []struct {
+		handler func(*state, command, database.User) error
+		name    string
+		args    []string
+		want    string
+	}{
+		{handlerStarred, "starred", nil, "No starred posts"},
+		{handlerStar, "star", []string{id}, "Post starred"},
+		{handlerStar, "star", []string{id}, "already starred"},
+		{handlerStar, "star", []string{uuid.NewString()}, "doesn't exist"},
+		{handlerStarred, "starred", nil, "Title: " + posts[0].Title},
+		{handlerUnstar, "unstar", []string{id}, "Post unstarred"},
+		{handlerUnstar, "unstar", []string{id}, "was not starred"},
+		{handlerStarred, "starred", nil, "No starred posts"},
+	}
+	for _, step := range steps {
+		output, err := captureOutput(t, func() error {
+			return step.handler(s, command{name: step.name, args: step.args}, user)
+		})
+		if err != nil {
+			t.Fatalf("%s %v error = %v", step.name, step.args, err)
+		}
+		if !strings.Contains(output, step.want) {
+			t.Errorf("%s %v = %q, want it to contain %q", step.name, step.args, output, step.want)
+		}
+	}
+
+	output, _ := captureOutput(t, func() error {
+		if err := handlerStar(s, command{name: "star", args: []string{id}}, user); err != nil {
+			return err
+		}
+		return handlerStarred(s, command{name: "starred"}, user)
+	})
+	if strings.Contains(output, posts[1].Title) {
+		t.Errorf("starred = %q, want only the starred post", output)
+	}
+	if err := handlerStar(s, command{name: "star"}, user); err == nil {
+		t.Errorf("star without a post id, want an error")
+	}
+}
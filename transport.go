@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/Pradhyumna789/RSS/internal/config"
+	"github.com/Pradhyumna789/RSS/internal/httpcache"
+)
+
+// feedClient returns the client used to fetch feedURL, wrapped for recording or replaying
+// when the command was started with --record or --replay
+func feedClient(s *state, feedURL string) (*http.Client, error) {
+	if s.replayDir != "" {
+		return &http.Client{Transport: &httpcache.Replayer{Dir: s.replayDir}}, nil
+	}
+
+	client, err := s.httpClients.get(s.config.TLS, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.recordDir != "" {
+		return &http.Client{Transport: &httpcache.Recorder{Dir: s.recordDir, Next: client.Transport}}, nil
+	}
+
+	return client, nil
+}
+
+// httpClients keeps the clients built by newHTTPClient for the life of the process, so the TLS
+// files are read once and connections are kept alive between fetches. Every feed shares one
+// client, except feeds with a client certificate, which get their own.
+type httpClients struct {
+	mu     sync.Mutex
+	shared *http.Client
+	byFeed map[string]*http.Client
+}
+
+func (c *httpClients) get(cfg config.TLSConfig, feedURL string) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := cfg.ClientCertificates[feedURL]; !ok {
+		if c.shared == nil {
+			client, err := newHTTPClient(cfg, "")
+			if err != nil {
+				return nil, err
+			}
+			c.shared = client
+		}
+		return c.shared, nil
+	}
+
+	if client, ok := c.byFeed[feedURL]; ok {
+		return client, nil
+	}
+	client, err := newHTTPClient(cfg, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	if c.byFeed == nil {
+		c.byFeed = make(map[string]*http.Client)
+	}
+	c.byFeed[feedURL] = client
+	return client, nil
+}
+
+// newHTTPClient builds the client fetchFeed uses for a feed, trusting the system roots
+// plus any extra CA files from the config and presenting the feed's client certificate if one is set.
+// httpClients builds each client once, so the insecure_skip_verify warning is logged once per client.
+func newHTTPClient(cfg config.TLSConfig, feedURL string) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if len(cfg.RootCAFiles) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+
+		for _, caFile := range cfg.RootCAFiles {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("error in reading the CA file %s: %w", caFile, err)
+			}
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in the CA file %s", caFile)
+			}
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if clientCert, ok := cfg.ClientCertificates[feedURL]; ok {
+		cert, err := tls.LoadX509KeyPair(clientCert.CertFile, clientCert.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error in loading the client certificate for %s: %w", feedURL, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+		feeds := "every feed"
+		if feedURL != "" {
+			feeds = feedURL
+		}
+		log.Printf("WARNING: TLS certificate verification is DISABLED for %s (insecure_skip_verify is set, use this in lab environments only)", feeds)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
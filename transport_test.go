@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Pradhyumna789/RSS/internal/config"
+)
+
+func Test_feedClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	// the test server's certificate is self-signed, so it is its own CA
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		s := &state{config: &config.Config{}}
+		client, err := feedClient(s, server.URL)
+		if err != nil {
+			t.Fatalf("feedClient() error = %v", err)
+		}
+		if res, err := client.Get(server.URL); err == nil {
+			res.Body.Close()
+			t.Errorf("Get() succeeded without the CA configured")
+		}
+	})
+
+	t.Run("custom CA", func(t *testing.T) {
+		s := &state{config: &config.Config{TLS: config.TLSConfig{RootCAFiles: []string{caFile}}}}
+		client, err := feedClient(s, server.URL)
+		if err != nil {
+			t.Fatalf("feedClient() error = %v", err)
+		}
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() with the CA configured error = %v", err)
+		}
+		res.Body.Close()
+
+		// the CA file is read once, later fetches reuse the client and its connections
+		if err := os.Remove(caFile); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+		again, err := feedClient(s, server.URL+"/other")
+		if err != nil {
+			t.Fatalf("second feedClient() error = %v", err)
+		}
+		if again != client {
+			t.Errorf("feedClient() built a new client for the second fetch")
+		}
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		certFile := filepath.Join(t.TempDir(), "client.crt")
+		keyFile := filepath.Join(t.TempDir(), "client.key")
+		s := &state{config: &config.Config{TLS: config.TLSConfig{
+			ClientCertificates: map[string]config.ClientCertificate{
+				server.URL + "/mtls": {CertFile: certFile, KeyFile: keyFile},
+			},
+		}}}
+		if _, err := feedClient(s, server.URL+"/mtls"); err == nil {
+			t.Errorf("feedClient() with a missing client certificate error = nil")
+		}
+		shared, err := feedClient(s, server.URL)
+		if err != nil {
+			t.Fatalf("feedClient() error = %v", err)
+		}
+		if again, _ := feedClient(s, server.URL+"/other"); again != shared {
+			t.Errorf("feeds without a client certificate don't share a client")
+		}
+	})
+
+	t.Run("insecure warning", func(t *testing.T) {
+		// the test server's own key pair doubles as a client certificate
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+		key, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+		if err != nil {
+			t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+		}
+		if err := os.WriteFile(certFile, caPEM, 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+
+		var logged bytes.Buffer
+		log.SetOutput(&logged)
+		defer log.SetOutput(os.Stderr)
+
+		mtlsURL := server.URL + "/mtls"
+		s := &state{config: &config.Config{TLS: config.TLSConfig{
+			InsecureSkipVerify: true,
+			ClientCertificates: map[string]config.ClientCertificate{mtlsURL: {CertFile: certFile, KeyFile: keyFile}},
+		}}}
+		for _, feedURL := range []string{server.URL, mtlsURL, server.URL + "/other", mtlsURL} {
+			if _, err := feedClient(s, feedURL); err != nil {
+				t.Fatalf("feedClient(%s) error = %v", feedURL, err)
+			}
+		}
+
+		// the test server logs its handshake errors to the same logger
+		var warnings []string
+		for _, line := range strings.Split(logged.String(), "\n") {
+			if strings.Contains(line, "WARNING") {
+				warnings = append(warnings, line)
+			}
+		}
+		if len(warnings) != 2 || !strings.Contains(warnings[0], "DISABLED for every feed") || !strings.Contains(warnings[1], "DISABLED for "+mtlsURL) {
+			t.Errorf("logged %q, want one warning for the shared client and one for the client certificate feed", warnings)
+		}
+	})
+}